@@ -0,0 +1,277 @@
+package gofin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+	"go.uber.org/zap"
+)
+
+// Backtest replays historical bars day by day through a TradingSystem backed by a SimBroker.
+// On each day the resting orders are matched against the day's bar first, then the
+// watchlist is filtered and ranked on the bars up to and including that day and the
+// resulting orders are placed at the day's close, just like Run does live.
+type Backtest struct {
+	system *TradingSystem
+	broker *brokers.SimBroker
+	replay *replayProvider
+	days   int
+}
+
+// Trade is a closed round trip of a position or a part of it.
+type Trade struct {
+	Symbol     string
+	Quantity   float64
+	EntryPrice float64
+	ExitPrice  float64
+	EntryBar   int
+	ExitBar    int
+	EntryTime  time.Time
+	ExitTime   time.Time
+	// PnL is the profit after the commission of the entry and the exit, both prorated by
+	// quantity if the fills close or open several trades.
+	PnL float64
+}
+
+type BacktestResult struct {
+	Trades []Trade
+	Fills  []brokers.Order
+	// Equity holds the account equity at the close of every replayed bar starting with bar Start.
+	Equity []float64
+	// Time holds the timestamps of the replayed bars if the histories carry timestamps.
+	Time  []time.Time
+	Start int
+}
+
+// NewBacktest creates a backtest of the watchlist over the given histories. Histories with
+// timestamps are aligned on their common dates; histories without must all cover the same
+// days. The system starts with the given cash and holds at most maxPositions positions.
+func NewBacktest(watchlist *utils.Watchlist, history map[string]*indicators.BarHistory, cash float64, maxPositions int) (*Backtest, error) {
+	if hasTime(history) {
+		aligned, err := indicators.AlignHistories(history)
+		if err != nil {
+			return nil, err
+		}
+		history = aligned
+	}
+
+	days := -1
+	for symbol, bars := range history {
+		if bars == nil {
+			return nil, fmt.Errorf("missing history for %s", symbol)
+		}
+		if days >= 0 && len(bars.Close) != days {
+			return nil, fmt.Errorf("history of %s has %d bars, want %d", symbol, len(bars.Close), days)
+		}
+		days = len(bars.Close)
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("no history to replay")
+	}
+
+	replay := &replayProvider{history: history}
+	broker := brokers.Sim(replay, cash)
+	broker.SetClock(replay.now)
+	system := NewTradingSystem(broker, watchlist, nil, replay)
+	system.SetMaxPositions(maxPositions)
+
+	return &Backtest{
+		system: system,
+		broker: broker,
+		replay: replay,
+		days:   days,
+	}, nil
+}
+
+// Broker returns the simulated broker, e.g. to configure commission and slippage.
+func (bt *Backtest) Broker() *brokers.SimBroker {
+	return bt.broker
+}
+
+// System returns the trading system under test, e.g. to add exit rules.
+func (bt *Backtest) System() *TradingSystem {
+	return bt.system
+}
+
+// SetLookback sets the number of bars handed to the filters and rankings. The replay starts
+// at the first bar for which that many bars are available.
+func (bt *Backtest) SetLookback(n int) {
+	bt.system.lookback = n
+}
+
+func (bt *Backtest) Run() (*BacktestResult, error) {
+	start := bt.system.lookback - 1
+	if start < 0 || start >= bt.days {
+		return nil, fmt.Errorf("invalid lookback: %d for %d bars", bt.system.lookback, bt.days)
+	}
+
+	log.Info("Run backtest", zap.Int("Bars", bt.days), zap.Int("Lookback", bt.system.lookback))
+
+	res := &BacktestResult{Start: start}
+	book := newTradeBook(bt.broker.Commission)
+
+	for day := start; day < bt.days; day++ {
+		bt.replay.day = day
+
+		if err := bt.broker.ProcessBars(); err != nil {
+			return nil, err
+		}
+		if err := bt.collectFills(res, book, day); err != nil {
+			return nil, err
+		}
+
+		openPositions := bt.system.CheckOpenPositions()
+		if openPositions < bt.system.maxPositions {
+			assets, history := bt.system.selectAssets()
+			for _, order := range bt.system.prepareOrders(assets, history, openPositions) {
+				if _, err := bt.broker.PlaceOrder(order); err != nil {
+					log.Warn("Error sending order to broker", zap.Error(err))
+				}
+			}
+		}
+		if err := bt.collectFills(res, book, day); err != nil {
+			return nil, err
+		}
+
+		equity, err := bt.broker.Equity()
+		if err != nil {
+			return nil, err
+		}
+		res.Equity = append(res.Equity, equity)
+		if t := bt.replay.now(); !t.IsZero() {
+			res.Time = append(res.Time, t)
+		}
+	}
+
+	res.Trades = book.trades
+	return res, nil
+}
+
+// collectFills appends the orders filled since the last call to the result.
+func (bt *Backtest) collectFills(res *BacktestResult, book *tradeBook, day int) error {
+	filled, err := bt.broker.GetFilledOrders()
+	if err != nil {
+		return err
+	}
+	for _, order := range filled {
+		if book.seen[order.ID] {
+			continue
+		}
+		book.seen[order.ID] = true
+		res.Fills = append(res.Fills, order)
+		book.add(order, day, bt.replay.now())
+	}
+	return nil
+}
+
+// tradeBook matches fills into round trips on a first in, first out basis and charges the
+// commission per fill.
+type tradeBook struct {
+	commission float64
+	seen       map[string]bool
+	lots       map[string][]lot
+	trades     []Trade
+}
+
+type lot struct {
+	qty   float64
+	price float64
+	// fee is the commission of the entry per share
+	fee  float64
+	bar  int
+	time time.Time
+}
+
+func newTradeBook(commission float64) *tradeBook {
+	return &tradeBook{commission: commission, seen: make(map[string]bool), lots: make(map[string][]lot)}
+}
+
+func (b *tradeBook) add(order brokers.Order, bar int, t time.Time) {
+	qty, _ := order.FilledQty.Float64()
+	price, _ := order.FilledAvgPrice.Float64()
+	fee := 0.0
+	if qty != 0 {
+		fee = b.commission / qty
+	}
+	if order.Side == brokers.Sell {
+		qty = -qty
+	}
+
+	lots := b.lots[order.Symbol]
+	for len(lots) > 0 && qty != 0 && lots[0].qty*qty < 0 {
+		open := &lots[0]
+		closed := qty
+		if abs(closed) > abs(open.qty) {
+			closed = -open.qty
+		}
+		b.trades = append(b.trades, Trade{
+			Symbol:     order.Symbol,
+			Quantity:   -closed,
+			EntryPrice: open.price,
+			ExitPrice:  price,
+			EntryBar:   open.bar,
+			ExitBar:    bar,
+			EntryTime:  open.time,
+			ExitTime:   t,
+			PnL:        -closed*(price-open.price) - abs(closed)*(open.fee+fee),
+		})
+		open.qty += closed
+		qty -= closed
+		if open.qty == 0 {
+			lots = lots[1:]
+		}
+	}
+	if qty != 0 {
+		lots = append(lots, lot{qty: qty, price: price, fee: fee, bar: bar, time: t})
+	}
+	b.lots[order.Symbol] = lots
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// replayProvider serves the histories up to and including the current day.
+type replayProvider struct {
+	history map[string]*indicators.BarHistory
+	day     int
+}
+
+func (p *replayProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	bars, ok := p.history[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no history for %s", symbol)
+	}
+	end := p.day + 1
+	if end < period {
+		return nil, fmt.Errorf("Not enough bars for %v", symbol)
+	}
+	res := bars.Sub(end-period, end)
+	return &res, nil
+}
+
+// now returns the timestamp of the current day or the zero time if the histories carry none.
+func (p *replayProvider) now() time.Time {
+	for _, bars := range p.history {
+		if !bars.HasTime() {
+			return time.Time{}
+		}
+		return bars.Time[p.day]
+	}
+	return time.Time{}
+}
+
+func hasTime(history map[string]*indicators.BarHistory) bool {
+	for _, bars := range history {
+		if bars == nil || !bars.HasTime() {
+			return false
+		}
+	}
+	return len(history) > 0
+}
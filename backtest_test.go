@@ -0,0 +1,179 @@
+package gofin
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+	"github.com/shopspring/decimal"
+)
+
+func testHistory(closes ...float64) *indicators.BarHistory {
+	bars := &indicators.BarHistory{}
+	for _, c := range closes {
+		bars.Open = append(bars.Open, c)
+		bars.High = append(bars.High, c+1)
+		bars.Low = append(bars.Low, c-1)
+		bars.Close = append(bars.Close, c)
+		bars.Volume = append(bars.Volume, 1000)
+	}
+	return bars
+}
+
+// day returns the given day of June 2023.
+func day(d int) time.Time {
+	return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+// datedHistory returns testHistory with daily timestamps.
+func datedHistory(closes ...float64) *indicators.BarHistory {
+	bars := testHistory(closes...)
+	for i := range bars.Close {
+		bars.Time = append(bars.Time, day(1+i))
+	}
+	return bars
+}
+
+func TestBacktestBuysRisingAsset(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"UP":   testHistory(10, 11, 12, 13, 14, 15),
+		"DOWN": testHistory(20, 19, 18, 17, 16, 15),
+	}
+	watchlist := utils.NewWatchlist(
+		[]utils.Asset{{Symbol: "DOWN"}, {Symbol: "UP"}},
+		[]utils.Filter{*utils.NewFilter(indicators.ROC(nil, 1), utils.GT, 0.0)},
+		nil,
+	)
+
+	bt, err := NewBacktest(watchlist, history, 1000, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	bt.SetLookback(3)
+
+	res, err := bt.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	if len(res.Fills) != 1 {
+		t.Fatalf("Expected 1 fill, got %d", len(res.Fills))
+	}
+	if res.Fills[0].Symbol != "UP" || !res.Fills[0].FilledQty.Equal(decimal.NewFromInt(83)) {
+		t.Errorf("Unexpected fill: %s %s", res.Fills[0].Symbol, res.Fills[0].FilledQty)
+	}
+	if len(res.Equity) != 4 || res.Start != 2 {
+		t.Fatalf("Expected 4 equity values starting at bar 2, got %d at %d", len(res.Equity), res.Start)
+	}
+	if want := 1000 - 83*12 + 83*15.0; res.Equity[3] != want {
+		t.Errorf("Expected final equity %v, got %v", want, res.Equity[3])
+	}
+}
+
+func TestBacktestUnalignedHistory(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"A": testHistory(1, 2, 3),
+		"B": testHistory(1, 2),
+	}
+	_, err := NewBacktest(utils.NewWatchlist(nil, nil, nil), history, 1000, 1)
+	if err == nil {
+		t.Errorf("Want error for unaligned history")
+	}
+}
+
+func TestBacktestAlignsDatedHistory(t *testing.T) {
+	a := testHistory(10, 11, 12, 13)
+	b := testHistory(20, 21, 22)
+	for i := range a.Close {
+		a.Time = append(a.Time, day(12+i))
+	}
+	for _, d := range []int{12, 14, 15} {
+		b.Time = append(b.Time, day(d))
+	}
+	watchlist := utils.NewWatchlist([]utils.Asset{{Symbol: "A"}}, nil, nil)
+
+	bt, err := NewBacktest(watchlist, map[string]*indicators.BarHistory{"A": a, "B": b}, 1000, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	bt.SetLookback(1)
+	res, err := bt.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if len(res.Time) != 3 || res.Time[1].Day() != 14 {
+		t.Fatalf("Expected 3 aligned days, got %v", res.Time)
+	}
+	if !res.Fills[0].FilledAt.Equal(res.Time[0]) {
+		t.Errorf("Expected fill at %v, got %v", res.Time[0], res.Fills[0].FilledAt)
+	}
+}
+
+func TestTradeBookFifo(t *testing.T) {
+	book := newTradeBook(0)
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Buy, FilledQty: decimal.NewFromInt(10), FilledAvgPrice: decimal.NewFromInt(100)}, 1, time.Time{})
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Sell, FilledQty: decimal.NewFromInt(4), FilledAvgPrice: decimal.NewFromInt(110)}, 2, time.Time{})
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Sell, FilledQty: decimal.NewFromInt(6), FilledAvgPrice: decimal.NewFromInt(90)}, 3, time.Time{})
+
+	if len(book.trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %d", len(book.trades))
+	}
+	if book.trades[0].PnL != 40 || book.trades[0].Quantity != 4 {
+		t.Errorf("Unexpected trade: %+v", book.trades[0])
+	}
+	if book.trades[1].PnL != -60 || book.trades[1].ExitBar != 3 {
+		t.Errorf("Unexpected trade: %+v", book.trades[1])
+	}
+	if len(book.lots["A"]) != 0 {
+		t.Errorf("Expected position to be closed, got %v", book.lots["A"])
+	}
+}
+
+func TestTradeBookCommission(t *testing.T) {
+	book := newTradeBook(5)
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Buy, FilledQty: decimal.NewFromInt(10), FilledAvgPrice: decimal.NewFromInt(100)}, 1, time.Time{})
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Sell, FilledQty: decimal.NewFromInt(4), FilledAvgPrice: decimal.NewFromInt(110)}, 2, time.Time{})
+	book.add(brokers.Order{Symbol: "A", Side: brokers.Sell, FilledQty: decimal.NewFromInt(6), FilledAvgPrice: decimal.NewFromInt(110)}, 3, time.Time{})
+
+	// 4 of 10 shares of the entry fee and the whole exit fee
+	if pnl := book.trades[0].PnL; math.Abs(pnl-(40-2-5)) > 1e-9 {
+		t.Errorf("Expected PnL 33, got %v", pnl)
+	}
+	total := book.trades[0].PnL + book.trades[1].PnL
+	if math.Abs(total-(100-15)) > 1e-9 {
+		t.Errorf("Expected total PnL 85 after 3 commissions, got %v", total)
+	}
+}
+
+func TestBacktestTradesMatchEquity(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"A": testHistory(10, 11, 12, 13, 12, 11, 10),
+	}
+	watchlist := utils.NewWatchlist(
+		[]utils.Asset{{Symbol: "A"}},
+		[]utils.Filter{*utils.NewFilter(indicators.ROC(nil, 1), utils.GT, 0.0)},
+		nil,
+	)
+	bt, _ := NewBacktest(watchlist, history, 1000, 1)
+	bt.SetLookback(2)
+	bt.broker.Commission = 1
+	bt.System().AddExitRule(PercentStop{Percent: 5})
+
+	res, err := bt.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if len(res.Trades) == 0 {
+		t.Fatalf("Expected trades")
+	}
+	pnl := 0.0
+	for _, trade := range res.Trades {
+		pnl += trade.PnL
+	}
+	if final := res.Equity[len(res.Equity)-1]; math.Abs(1000+pnl-final) > 1e-9 {
+		t.Errorf("Expected equity %v from the trades, got %v", 1000+pnl, final)
+	}
+}
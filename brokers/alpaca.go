@@ -7,6 +7,7 @@ import (
 	"github.com/d1l1x/gofin/utils"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"math"
 	"time"
 )
 
@@ -14,6 +15,9 @@ var log = utils.NewZapLogger("broker", utils.Debug) //.Sugar()
 
 const basePaperURL = "https://paper-api.alpaca.markets"
 
+var _ Broker = (*AlpacaBroker)(nil)
+var _ PositionTimer = (*AlpacaBroker)(nil)
+
 type AlpacaBroker struct {
 	trade *alpaca.Client
 	data  *marketdata.Client
@@ -103,6 +107,15 @@ func (broker *AlpacaBroker) Cash() (float64, error) {
 	return res, nil
 }
 
+func (broker *AlpacaBroker) Equity() (float64, error) {
+	account, err := broker.trade.GetAccount()
+	if err != nil {
+		return 0, fmt.Errorf("Equity: %w", err)
+	}
+	res, _ := account.Equity.Float64()
+	return res, nil
+}
+
 func (broker *AlpacaBroker) GetOpenPositions() ([]Position, error) {
 	positions, err := broker.trade.GetPositions()
 	if err != nil {
@@ -110,36 +123,66 @@ func (broker *AlpacaBroker) GetOpenPositions() ([]Position, error) {
 	}
 	res := make([]Position, len(positions))
 	for i, p := range positions {
-		qty, _ := p.Qty.Float64()
-		fillPrice, _ := p.AvgEntryPrice.Float64()
-		res[i] = Position{
-			Symbol:       p.Symbol,
-			Quantity:     qty,
-			ID:           p.AssetID,
-			AvgFillPrice: fillPrice,
-		}
+		res[i] = fromAlpacaPosition(p)
 	}
 	return res, nil
 }
 
-func (broker *AlpacaBroker) GetOpenPosition(symbol string) (*alpaca.Position, error) {
+// PositionOpenedAt returns the time of the fill that opened the position. It walks back
+// through the last 500 closed orders of the symbol and undoes their fills, including partial
+// exits, until the position is flat. Alpaca does not report the time with the position and
+// the lookup costs a request, hence callers should resolve it once per position.
+func (broker *AlpacaBroker) PositionOpenedAt(p Position) (time.Time, error) {
+	orders, err := broker.trade.GetOrders(alpaca.GetOrdersRequest{
+		Status:    "closed",
+		Limit:     500,
+		Direction: "desc",
+		Symbols:   []string{p.Symbol},
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	entry := alpaca.Buy
+	if p.Quantity < 0 {
+		entry = alpaca.Sell
+	}
+	remaining := decimal.NewFromFloat(math.Abs(p.Quantity))
+	for _, o := range orders {
+		if o.FilledAt == nil || o.FilledQty.IsZero() {
+			continue
+		}
+		if o.Side == entry {
+			remaining = remaining.Sub(o.FilledQty)
+		} else {
+			remaining = remaining.Add(o.FilledQty)
+		}
+		if !remaining.IsPositive() {
+			return *o.FilledAt, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("opening fill of %s not among the last %d orders", p.Symbol, len(orders))
+}
+
+func (broker *AlpacaBroker) GetOpenPosition(symbol string) (*Position, error) {
 	position, err := broker.trade.GetPosition(symbol)
 	if err != nil {
 		return nil, fmt.Errorf("GetOpenPosition: %w", err)
 	}
-	return position, nil
+	res := fromAlpacaPosition(*position)
+	return &res, nil
 }
 
-func (broker *AlpacaBroker) GetOrder(orderId string) (*alpaca.Order, error) {
+func (broker *AlpacaBroker) GetOrder(orderId string) (*Order, error) {
 	log.Debug("GetOrder", zap.String("orderId", orderId))
 	order, err := broker.trade.GetOrder(orderId)
 	if err != nil {
 		return nil, fmt.Errorf("GetOrder: %w", err)
 	}
-	return order, nil
+	res := fromAlpacaOrder(*order)
+	return &res, nil
 }
 
-func (broker *AlpacaBroker) GetOpenOrders() ([]alpaca.Order, error) {
+func (broker *AlpacaBroker) GetOpenOrders() ([]Order, error) {
 	orders, err := broker.trade.GetOrders(alpaca.GetOrdersRequest{
 		Status: "open",
 		Until:  time.Now(),
@@ -147,7 +190,11 @@ func (broker *AlpacaBroker) GetOpenOrders() ([]alpaca.Order, error) {
 	if err != nil {
 		return nil, fmt.Errorf("GetOpenOrders: %w", err)
 	}
-	return orders, nil
+	res := make([]Order, len(orders))
+	for i, o := range orders {
+		res[i] = fromAlpacaOrder(o)
+	}
+	return res, nil
 }
 
 func (broker *AlpacaBroker) CancelOrder(orderId string) error {
@@ -167,48 +214,63 @@ func (broker *AlpacaBroker) CancelAllOrders() error {
 	return nil
 }
 
-func (broker *AlpacaBroker) LimitOrder(side alpaca.Side, symbol string, quantity int, limitPrice float64, timeInForce alpaca.TimeInForce) (string, error) {
-	qty := decimal.NewFromInt(int64(quantity))
-	price := decimal.NewFromFloat(limitPrice)
-	order, err := broker.trade.PlaceOrder(alpaca.PlaceOrderRequest{
-		Symbol:      symbol,
+// PlaceOrder submits the given order and returns the ID assigned by Alpaca.
+func (broker *AlpacaBroker) PlaceOrder(order Order) (string, error) {
+	qty := order.Qty
+	req := alpaca.PlaceOrderRequest{
+		Symbol:      order.Symbol,
 		Qty:         &qty,
+		Side:        alpaca.Side(order.Side),
+		Type:        alpaca.OrderType(order.Type),
+		TimeInForce: alpaca.TimeInForce(order.TimeInForce),
+	}
+	if req.TimeInForce == "" {
+		req.TimeInForce = alpaca.Day
+	}
+	if !order.LimitPrice.IsZero() {
+		limit := order.LimitPrice
+		req.LimitPrice = &limit
+	}
+	if !order.StopPrice.IsZero() {
+		stop := order.StopPrice
+		req.StopPrice = &stop
+	}
+	placed, err := broker.trade.PlaceOrder(req)
+	if err != nil {
+		log.Warn("Order failed", zap.String("type", string(order.Type)), zap.String("symbol", order.Symbol), zap.Error(err))
+		return "", fmt.Errorf("PlaceOrder: %w", err)
+	}
+	log.Info("Order placed",
+		zap.String("type", string(order.Type)),
+		zap.String("id", placed.ID),
+		zap.String("symbol", order.Symbol),
+		zap.String("quantity", order.Qty.String()),
+	)
+	return placed.ID, nil
+}
+
+func (broker *AlpacaBroker) LimitOrder(side Side, symbol string, quantity int, limitPrice float64, timeInForce TimeInForce) (string, error) {
+	return broker.PlaceOrder(Order{
+		Symbol:      symbol,
+		Qty:         decimal.NewFromInt(int64(quantity)),
 		Side:        side,
-		Type:        alpaca.Limit,
-		LimitPrice:  &price,
+		Type:        Limit,
+		LimitPrice:  decimal.NewFromFloat(limitPrice),
 		TimeInForce: timeInForce,
 	})
-	if err == nil {
-		log.Info("Order placed", zap.String("type", "limit"), zap.String("id", order.ID))
-	} else {
-		log.Warn("Order failed", zap.String("type", "limit"), zap.Error(err))
-	}
-	return order.ID, nil
 }
 
-func (broker *AlpacaBroker) MarketOrder(side alpaca.Side, symbol string, quantity int, timeInForce alpaca.TimeInForce) (string, error) {
-	qty := decimal.NewFromInt(int64(quantity))
-	order, err := broker.trade.PlaceOrder(alpaca.PlaceOrderRequest{
+func (broker *AlpacaBroker) MarketOrder(side Side, symbol string, quantity int, timeInForce TimeInForce) (string, error) {
+	return broker.PlaceOrder(Order{
 		Symbol:      symbol,
-		Qty:         &qty,
+		Qty:         decimal.NewFromInt(int64(quantity)),
 		Side:        side,
-		Type:        alpaca.Market,
+		Type:        Market,
 		TimeInForce: timeInForce,
 	})
-	if err == nil {
-		log.Info("Order placed",
-			zap.String("type", "market"),
-			zap.String("id", order.ID),
-			zap.String("symbol", symbol),
-			zap.Int("quantity", quantity),
-		)
-	} else {
-		log.Warn("Order failed", zap.String("type", "market"), zap.String("symbol", symbol), zap.Error(err))
-	}
-	return order.ID, nil
 }
 
-func (broker *AlpacaBroker) GetListOfAssets(status, class, exchange string) ([]alpaca.Asset, error) {
+func (broker *AlpacaBroker) GetListOfAssets(status, class, exchange string) ([]Asset, error) {
 	if status == "" {
 		status = "active"
 	}
@@ -224,13 +286,13 @@ func (broker *AlpacaBroker) GetListOfAssets(status, class, exchange string) ([]a
 	if err != nil {
 		return nil, fmt.Errorf("GetListOfAssets: %w", err)
 	}
-	assets := make([]alpaca.Asset, 0)
+	assets := make([]Asset, 0)
 	log.Debug("Filter list of assets", zap.Strings("filters", []string{"tradable", "non-OTC"}))
 	for _, asset := range allAssets {
 		if !asset.Tradable || asset.Exchange == "OTC" {
 			continue
 		}
-		assets = append(assets, asset)
+		assets = append(assets, fromAlpacaAsset(asset))
 	}
 	return assets, nil
 }
@@ -247,3 +309,56 @@ func (broker *AlpacaBroker) GetSymbolBars(symbol string, period int) ([]marketda
 	}
 	return bars, nil
 }
+
+func fromAlpacaPosition(p alpaca.Position) Position {
+	qty, _ := p.Qty.Float64()
+	fillPrice, _ := p.AvgEntryPrice.Float64()
+	return Position{
+		Symbol:       p.Symbol,
+		Quantity:     qty,
+		ID:           p.AssetID,
+		AvgFillPrice: fillPrice,
+	}
+}
+
+func fromAlpacaOrder(o alpaca.Order) Order {
+	res := Order{
+		ID:          o.ID,
+		Symbol:      o.Symbol,
+		Side:        Side(o.Side),
+		Type:        OrderType(o.Type),
+		TimeInForce: TimeInForce(o.TimeInForce),
+		Status:      OrderStatus(o.Status),
+		FilledQty:   o.FilledQty,
+		SubmittedAt: o.SubmittedAt,
+	}
+	if o.Qty != nil {
+		res.Qty = *o.Qty
+	}
+	if o.LimitPrice != nil {
+		res.LimitPrice = *o.LimitPrice
+	}
+	if o.StopPrice != nil {
+		res.StopPrice = *o.StopPrice
+	}
+	if o.FilledAvgPrice != nil {
+		res.FilledAvgPrice = *o.FilledAvgPrice
+	}
+	if o.FilledAt != nil {
+		res.FilledAt = *o.FilledAt
+	}
+	return res
+}
+
+func fromAlpacaAsset(a alpaca.Asset) Asset {
+	return Asset{
+		ID:           a.ID,
+		Symbol:       a.Symbol,
+		Name:         a.Name,
+		Class:        string(a.Class),
+		Exchange:     a.Exchange,
+		Tradable:     a.Tradable,
+		Shortable:    a.Shortable,
+		Fractionable: a.Fractionable,
+	}
+}
@@ -0,0 +1,117 @@
+package brokers
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Broker is the broker-neutral API used by the trading system. Concrete brokers
+// translate their own account, position and order representations into the
+// types defined in this file.
+type Broker interface {
+	BuyingPower() (float64, error)
+	Cash() (float64, error)
+	Equity() (float64, error)
+	GetOpenPositions() ([]Position, error)
+	GetOpenPosition(symbol string) (*Position, error)
+	PlaceOrder(order Order) (string, error)
+	GetOrder(orderId string) (*Order, error)
+	GetOpenOrders() ([]Order, error)
+	CancelOrder(orderId string) error
+	CancelAllOrders() error
+	GetListOfAssets(status, class, exchange string) ([]Asset, error)
+}
+
+// PositionTimer is implemented by brokers that do not report Position.OpenedAt with the
+// position but can look it up.
+type PositionTimer interface {
+	PositionOpenedAt(p Position) (time.Time, error)
+}
+
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+type OrderType string
+
+const (
+	Market    OrderType = "market"
+	Limit     OrderType = "limit"
+	Stop      OrderType = "stop"
+	StopLimit OrderType = "stop_limit"
+)
+
+type TimeInForce string
+
+const (
+	Day TimeInForce = "day"
+	GTC TimeInForce = "gtc"
+	OPG TimeInForce = "opg"
+	IOC TimeInForce = "ioc"
+	FOK TimeInForce = "fok"
+	CLS TimeInForce = "cls"
+)
+
+type OrderStatus string
+
+const (
+	New             OrderStatus = "new"
+	Accepted        OrderStatus = "accepted"
+	PartiallyFilled OrderStatus = "partially_filled"
+	Filled          OrderStatus = "filled"
+	Canceled        OrderStatus = "canceled"
+	Expired         OrderStatus = "expired"
+	Rejected        OrderStatus = "rejected"
+)
+
+// IsOpen reports whether an order with this status may still be filled.
+func (s OrderStatus) IsOpen() bool {
+	switch s {
+	case Filled, Canceled, Expired, Rejected:
+		return false
+	}
+	return true
+}
+
+type Position struct {
+	Symbol       string
+	ID           string
+	Quantity     float64
+	AvgFillPrice float64
+	// OpenedAt is the time of the fill that opened the position, zero if unknown, see
+	// PositionTimer.
+	OpenedAt time.Time
+}
+
+// Order describes an order before and after it has been submitted to a broker.
+// A zero LimitPrice or StopPrice means the price is not set.
+type Order struct {
+	ID             string
+	Symbol         string
+	Side           Side
+	Type           OrderType
+	TimeInForce    TimeInForce
+	Qty            decimal.Decimal
+	LimitPrice     decimal.Decimal
+	StopPrice      decimal.Decimal
+	Status         OrderStatus
+	FilledQty      decimal.Decimal
+	FilledAvgPrice decimal.Decimal
+	SubmittedAt    time.Time
+	FilledAt       time.Time
+}
+
+type Asset struct {
+	ID           string
+	Symbol       string
+	Name         string
+	Class        string
+	Exchange     string
+	Tradable     bool
+	Shortable    bool
+	Fractionable bool
+}
@@ -0,0 +1,464 @@
+package brokers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/providers"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var _ Broker = (*SimBroker)(nil)
+
+// SimBroker is an in-memory paper broker. It keeps cash, positions and an order
+// book and fills orders against the most recent bar returned by its data provider.
+//
+// Market orders are filled on submission at the close of the latest bar, which
+// matches a system trading on close. Limit and stop orders that are marketable at
+// that close are filled immediately as well; all others rest in the book and are
+// matched against the next bar fed by ProcessBars:
+//   - market orders fill at the open,
+//   - limit orders fill at the limit or a better open once the bar trades through it,
+//   - stop orders fill at the stop or a worse open once the bar touches it,
+//   - stop limit orders become limit orders once the stop is touched.
+//
+// Day orders that are not filled on that next bar expire, other orders rest until
+// they are filled or canceled.
+//
+// The account is a cash account without margin: the buying power is the cash less the
+// notional of the open buy orders, buy orders that cannot be paid for are rejected and
+// so are sell orders exceeding the long position, i.e. short selling is not supported.
+type SimBroker struct {
+	// Commission is charged per filled order.
+	Commission float64
+	// Slippage is the fraction of the fill price added to buys and subtracted from sells.
+	Slippage float64
+	// Assets is the universe returned by GetListOfAssets.
+	Assets []Asset
+
+	mu        sync.Mutex
+	provider  providers.DataProvider
+	cash      float64
+	positions map[string]*Position
+	orders    []*Order
+	lastBar   map[string]simBar
+	nextID    int
+	now       func() time.Time
+}
+
+type simBar struct {
+	open, high, low, close float64
+}
+
+func Sim(provider providers.DataProvider, cash float64) *SimBroker {
+	log.Info("Setup Broker", zap.String("name", "Sim"), zap.Float64("cash", cash))
+	return &SimBroker{
+		provider:  provider,
+		cash:      cash,
+		positions: make(map[string]*Position),
+		lastBar:   make(map[string]simBar),
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces the clock used to time stamp orders and fills.
+func (broker *SimBroker) SetClock(now func() time.Time) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.now = now
+}
+
+func (broker *SimBroker) AddAsset(a Asset) {
+	broker.Assets = append(broker.Assets, a)
+}
+
+// BuyingPower returns the cash not reserved by open buy orders.
+func (broker *SimBroker) BuyingPower() (float64, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return broker.cash - broker.reserved(""), nil
+}
+
+func (broker *SimBroker) Cash() (float64, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return broker.cash, nil
+}
+
+// Equity returns the cash plus the positions valued at the last known close.
+func (broker *SimBroker) Equity() (float64, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	equity := broker.cash
+	for symbol, p := range broker.positions {
+		price := p.AvgFillPrice
+		if bar, ok := broker.lastBar[symbol]; ok {
+			price = bar.close
+		}
+		equity += p.Quantity * price
+	}
+	return equity, nil
+}
+
+func (broker *SimBroker) GetOpenPositions() ([]Position, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	res := make([]Position, 0, len(broker.positions))
+	for _, p := range broker.positions {
+		res = append(res, *p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Symbol < res[j].Symbol })
+	return res, nil
+}
+
+func (broker *SimBroker) GetOpenPosition(symbol string) (*Position, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	p, ok := broker.positions[symbol]
+	if !ok {
+		return nil, fmt.Errorf("GetOpenPosition: no open position for %s", symbol)
+	}
+	res := *p
+	return &res, nil
+}
+
+// PlaceOrder adds the order to the book and tries to fill it against the latest
+// bar of its symbol.
+func (broker *SimBroker) PlaceOrder(order Order) (string, error) {
+	if order.Symbol == "" {
+		return "", fmt.Errorf("PlaceOrder: missing symbol")
+	}
+	if !order.Qty.IsPositive() {
+		return "", fmt.Errorf("PlaceOrder: invalid quantity %s", order.Qty)
+	}
+	switch order.Type {
+	case Limit:
+		if !order.LimitPrice.IsPositive() {
+			return "", fmt.Errorf("PlaceOrder: limit order without limit price")
+		}
+	case Stop:
+		if !order.StopPrice.IsPositive() {
+			return "", fmt.Errorf("PlaceOrder: stop order without stop price")
+		}
+	case StopLimit:
+		if !order.LimitPrice.IsPositive() || !order.StopPrice.IsPositive() {
+			return "", fmt.Errorf("PlaceOrder: stop limit order without limit and stop price")
+		}
+	case Market:
+	default:
+		return "", fmt.Errorf("PlaceOrder: unsupported order type %q", order.Type)
+	}
+
+	bars, err := broker.provider.GetHistBars(order.Symbol, 1)
+	if err != nil {
+		return "", fmt.Errorf("PlaceOrder: %w", err)
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	broker.nextID++
+	order.ID = strconv.Itoa(broker.nextID)
+	order.Status = New
+	order.FilledQty = decimal.Zero
+	order.FilledAvgPrice = decimal.Zero
+	order.SubmittedAt = broker.now()
+	if order.TimeInForce == "" {
+		order.TimeInForce = Day
+	}
+	o := &order
+	broker.orders = append(broker.orders, o)
+
+	bar, ok := lastSimBar(bars)
+	if !ok {
+		return o.ID, nil
+	}
+	broker.lastBar[o.Symbol] = bar
+	if o.Side == Buy && notional(o, bar.close)+broker.Commission > broker.cash-broker.reserved(o.ID) {
+		log.Warn("Order rejected", zap.String("id", o.ID), zap.String("symbol", o.Symbol), zap.String("reason", "insufficient buying power"))
+		o.Status = Rejected
+		return o.ID, nil
+	}
+	// On submission the order can only trade at the current price.
+	broker.match(o, simBar{open: bar.close, high: bar.close, low: bar.close, close: bar.close})
+	log.Info("Order placed",
+		zap.String("type", string(o.Type)),
+		zap.String("id", o.ID),
+		zap.String("symbol", o.Symbol),
+		zap.String("status", string(o.Status)),
+	)
+	return o.ID, nil
+}
+
+// ProcessBars fetches the latest bar for every symbol with open orders or
+// positions and matches the open orders against it.
+func (broker *SimBroker) ProcessBars() error {
+	broker.mu.Lock()
+	symbols := make(map[string]bool)
+	for _, o := range broker.orders {
+		if o.Status.IsOpen() {
+			symbols[o.Symbol] = true
+		}
+	}
+	for symbol := range broker.positions {
+		symbols[symbol] = true
+	}
+	broker.mu.Unlock()
+
+	sorted := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		sorted = append(sorted, symbol)
+	}
+	sort.Strings(sorted)
+
+	for _, symbol := range sorted {
+		bars, err := broker.provider.GetHistBars(symbol, 1)
+		if err != nil {
+			return fmt.Errorf("ProcessBars: %w", err)
+		}
+		bar, ok := lastSimBar(bars)
+		if !ok {
+			continue
+		}
+		broker.mu.Lock()
+		broker.lastBar[symbol] = bar
+		for _, o := range broker.orders {
+			if o.Symbol != symbol || !o.Status.IsOpen() {
+				continue
+			}
+			broker.match(o, bar)
+			if o.Status.IsOpen() && o.TimeInForce == Day {
+				o.Status = Expired
+			}
+		}
+		broker.mu.Unlock()
+	}
+	return nil
+}
+
+func (broker *SimBroker) GetOrder(orderId string) (*Order, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for _, o := range broker.orders {
+		if o.ID == orderId {
+			res := *o
+			return &res, nil
+		}
+	}
+	return nil, fmt.Errorf("GetOrder: unknown order %s", orderId)
+}
+
+func (broker *SimBroker) GetOpenOrders() ([]Order, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	res := make([]Order, 0)
+	for _, o := range broker.orders {
+		if o.Status.IsOpen() {
+			res = append(res, *o)
+		}
+	}
+	return res, nil
+}
+
+// GetFilledOrders returns all filled orders in the order they were submitted.
+func (broker *SimBroker) GetFilledOrders() ([]Order, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	res := make([]Order, 0)
+	for _, o := range broker.orders {
+		if o.Status == Filled {
+			res = append(res, *o)
+		}
+	}
+	return res, nil
+}
+
+func (broker *SimBroker) CancelOrder(orderId string) error {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for _, o := range broker.orders {
+		if o.ID == orderId {
+			if !o.Status.IsOpen() {
+				return fmt.Errorf("CancelOrder: order %s is %s", orderId, o.Status)
+			}
+			o.Status = Canceled
+			return nil
+		}
+	}
+	return fmt.Errorf("CancelOrder: unknown order %s", orderId)
+}
+
+func (broker *SimBroker) CancelAllOrders() error {
+	log.Info("Cancel all open orders")
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for _, o := range broker.orders {
+		if o.Status.IsOpen() {
+			o.Status = Canceled
+		}
+	}
+	return nil
+}
+
+func (broker *SimBroker) GetListOfAssets(status, class, exchange string) ([]Asset, error) {
+	assets := make([]Asset, 0)
+	for _, a := range broker.Assets {
+		if class != "" && a.Class != class {
+			continue
+		}
+		if exchange != "" && a.Exchange != exchange {
+			continue
+		}
+		if !a.Tradable || a.Exchange == "OTC" {
+			continue
+		}
+		assets = append(assets, a)
+	}
+	return assets, nil
+}
+
+// match fills the order if the bar trades through its price. The caller must hold the lock.
+func (broker *SimBroker) match(o *Order, bar simBar) {
+	price, ok := fillPrice(o, bar)
+	if !ok {
+		return
+	}
+	if o.Side == Buy {
+		price *= 1 + broker.Slippage
+	} else {
+		price *= 1 - broker.Slippage
+	}
+
+	qty, _ := o.Qty.Float64()
+	if o.Side == Buy && qty*price+broker.Commission > broker.cash {
+		log.Warn("Order rejected", zap.String("id", o.ID), zap.String("symbol", o.Symbol), zap.String("reason", "insufficient cash"))
+		o.Status = Rejected
+		return
+	}
+	if o.Side == Sell && qty > broker.longQuantity(o.Symbol)+1e-9 {
+		log.Warn("Order rejected", zap.String("id", o.ID), zap.String("symbol", o.Symbol), zap.String("reason", "insufficient position"))
+		o.Status = Rejected
+		return
+	}
+
+	signed := qty
+	if o.Side == Sell {
+		signed = -qty
+	}
+	broker.cash -= signed*price + broker.Commission
+	broker.updatePosition(o.Symbol, signed, price)
+
+	o.Status = Filled
+	o.FilledQty = o.Qty
+	o.FilledAvgPrice = decimal.NewFromFloat(price)
+	o.FilledAt = broker.now()
+}
+
+// reserved returns the notional of the open buy orders except the one with the given id,
+// valued at their limit or stop price or else at the last known close. The caller must hold
+// the lock.
+func (broker *SimBroker) reserved(except string) float64 {
+	res := 0.0
+	for _, o := range broker.orders {
+		if o.ID == except || o.Side != Buy || !o.Status.IsOpen() {
+			continue
+		}
+		res += notional(o, broker.lastBar[o.Symbol].close) + broker.Commission
+	}
+	return res
+}
+
+// notional returns the value of the order at its limit or stop price or else at the price.
+func notional(o *Order, price float64) float64 {
+	switch {
+	case o.LimitPrice.IsPositive():
+		price, _ = o.LimitPrice.Float64()
+	case o.StopPrice.IsPositive():
+		price, _ = o.StopPrice.Float64()
+	}
+	qty, _ := o.Qty.Float64()
+	return qty * price
+}
+
+// longQuantity returns the quantity held long in the symbol. The caller must hold the lock.
+func (broker *SimBroker) longQuantity(symbol string) float64 {
+	if p, ok := broker.positions[symbol]; ok && p.Quantity > 0 {
+		return p.Quantity
+	}
+	return 0
+}
+
+func (broker *SimBroker) updatePosition(symbol string, qty, price float64) {
+	p, ok := broker.positions[symbol]
+	if !ok {
+		broker.positions[symbol] = &Position{Symbol: symbol, ID: symbol, Quantity: qty, AvgFillPrice: price, OpenedAt: broker.now()}
+		return
+	}
+	total := p.Quantity + qty
+	switch {
+	case math.Abs(total) < 1e-9:
+		delete(broker.positions, symbol)
+		return
+	case p.Quantity*qty > 0:
+		// position is increased
+		p.AvgFillPrice = (p.Quantity*p.AvgFillPrice + qty*price) / total
+	}
+	p.Quantity = total
+}
+
+// fillPrice returns the price at which the order trades within the bar.
+func fillPrice(o *Order, bar simBar) (float64, bool) {
+	limit, _ := o.LimitPrice.Float64()
+	stop, _ := o.StopPrice.Float64()
+	buy := o.Side == Buy
+
+	switch o.Type {
+	case Market:
+		return bar.open, true
+	case Limit:
+		return limitFill(buy, limit, bar)
+	case Stop:
+		return stopFill(buy, stop, bar)
+	case StopLimit:
+		if _, triggered := stopFill(buy, stop, bar); !triggered {
+			return 0, false
+		}
+		o.Type = Limit
+		return limitFill(buy, limit, bar)
+	}
+	return 0, false
+}
+
+func limitFill(buy bool, limit float64, bar simBar) (float64, bool) {
+	if buy && bar.low <= limit {
+		return math.Min(bar.open, limit), true
+	}
+	if !buy && bar.high >= limit {
+		return math.Max(bar.open, limit), true
+	}
+	return 0, false
+}
+
+func stopFill(buy bool, stop float64, bar simBar) (float64, bool) {
+	if buy && bar.high >= stop {
+		return math.Max(bar.open, stop), true
+	}
+	if !buy && bar.low <= stop {
+		return math.Min(bar.open, stop), true
+	}
+	return 0, false
+}
+
+func lastSimBar(bars *indicators.BarHistory) (simBar, bool) {
+	if bars == nil || len(bars.Close) == 0 {
+		return simBar{}, false
+	}
+	i := len(bars.Close) - 1
+	return simBar{open: bars.Open[i], high: bars.High[i], low: bars.Low[i], close: bars.Close[i]}, true
+}
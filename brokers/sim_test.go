@@ -0,0 +1,244 @@
+package brokers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/shopspring/decimal"
+)
+
+type mockProvider struct {
+	bars map[string]*indicators.BarHistory
+}
+
+func (m *mockProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	bars, ok := m.bars[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	return bars, nil
+}
+
+func (m *mockProvider) setBar(symbol string, open, high, low, close float64) {
+	m.bars[symbol] = &indicators.BarHistory{
+		Open:   []float64{open},
+		High:   []float64{high},
+		Low:    []float64{low},
+		Close:  []float64{close},
+		Volume: []int64{1000},
+	}
+}
+
+func newMockSim(cash float64) (*SimBroker, *mockProvider) {
+	provider := &mockProvider{bars: make(map[string]*indicators.BarHistory)}
+	provider.setBar("AAPL", 100, 102, 98, 101)
+	return Sim(provider, cash), provider
+}
+
+func TestSimMarketOrderFilledAtClose(t *testing.T) {
+	broker, _ := newMockSim(10000)
+	now := time.Date(2024, 1, 2, 16, 0, 0, 0, time.UTC)
+	broker.SetClock(func() time.Time { return now })
+
+	id, err := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(10)})
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	order, _ := broker.GetOrder(id)
+	if order.Status != Filled {
+		t.Fatalf("Expected order to be filled, got %s", order.Status)
+	}
+	if !order.FilledAvgPrice.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Expected fill price 101, got %s", order.FilledAvgPrice)
+	}
+	cash, _ := broker.Cash()
+	if cash != 10000-1010 {
+		t.Errorf("Expected cash 8990, got %v", cash)
+	}
+	pos, err := broker.GetOpenPosition("AAPL")
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if pos.Quantity != 10 || pos.AvgFillPrice != 101 || !pos.OpenedAt.Equal(now) {
+		t.Errorf("Unexpected position: %+v", pos)
+	}
+}
+
+func TestSimLimitOrderRestsUntilTouched(t *testing.T) {
+	broker, provider := newMockSim(10000)
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, TimeInForce: GTC, Qty: decimal.NewFromInt(10), LimitPrice: decimal.NewFromInt(95)})
+	order, _ := broker.GetOrder(id)
+	if order.Status != New {
+		t.Fatalf("Expected order to rest in the book, got %s", order.Status)
+	}
+
+	provider.setBar("AAPL", 99, 100, 96, 97)
+	_ = broker.ProcessBars()
+	order, _ = broker.GetOrder(id)
+	if order.Status != New {
+		t.Fatalf("Expected order to rest in the book, got %s", order.Status)
+	}
+
+	provider.setBar("AAPL", 97, 98, 94, 96)
+	_ = broker.ProcessBars()
+	order, _ = broker.GetOrder(id)
+	if order.Status != Filled {
+		t.Fatalf("Expected order to be filled, got %s", order.Status)
+	}
+	if !order.FilledAvgPrice.Equal(decimal.NewFromInt(95)) {
+		t.Errorf("Expected fill price 95, got %s", order.FilledAvgPrice)
+	}
+}
+
+func TestSimDayOrderExpires(t *testing.T) {
+	broker, provider := newMockSim(10000)
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(10), LimitPrice: decimal.NewFromInt(95)})
+	provider.setBar("AAPL", 99, 100, 96, 97)
+	_ = broker.ProcessBars()
+	order, _ := broker.GetOrder(id)
+	if order.Status != Expired {
+		t.Fatalf("Expected day order to expire, got %s", order.Status)
+	}
+
+	provider.setBar("AAPL", 97, 98, 94, 96)
+	_ = broker.ProcessBars()
+	order, _ = broker.GetOrder(id)
+	if order.Status != Expired {
+		t.Errorf("Expected expired order not to fill, got %s", order.Status)
+	}
+}
+
+func TestSimOpenBuyOrdersReserveCash(t *testing.T) {
+	broker, _ := newMockSim(1000)
+
+	// reserves 10 * 95
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(10), LimitPrice: decimal.NewFromInt(95)})
+	bp, _ := broker.BuyingPower()
+	if bp != 1000-950 {
+		t.Errorf("Expected buying power 50, got %v", bp)
+	}
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(1), LimitPrice: decimal.NewFromInt(95)})
+	order, _ := broker.GetOrder(id)
+	if order.Status != Rejected {
+		t.Errorf("Expected order exceeding the buying power to be rejected, got %s", order.Status)
+	}
+	id, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(1)})
+	order, _ = broker.GetOrder(id)
+	if order.Status != Rejected {
+		t.Errorf("Expected market order exceeding the buying power to be rejected, got %s", order.Status)
+	}
+}
+
+func TestSimLimitOrderGapFillsAtOpen(t *testing.T) {
+	broker, provider := newMockSim(10000)
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(10), LimitPrice: decimal.NewFromInt(95)})
+	provider.setBar("AAPL", 90, 92, 89, 91)
+	_ = broker.ProcessBars()
+
+	order, _ := broker.GetOrder(id)
+	if !order.FilledAvgPrice.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("Expected fill price 90, got %s", order.FilledAvgPrice)
+	}
+}
+
+func TestSimStopOrderClosesPosition(t *testing.T) {
+	broker, provider := newMockSim(10000)
+
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(10)})
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Sell, Type: Stop, Qty: decimal.NewFromInt(10), StopPrice: decimal.NewFromInt(97)})
+
+	provider.setBar("AAPL", 99, 100, 96, 97)
+	_ = broker.ProcessBars()
+
+	order, _ := broker.GetOrder(id)
+	if order.Status != Filled || !order.FilledAvgPrice.Equal(decimal.NewFromInt(97)) {
+		t.Fatalf("Expected stop filled at 97, got %s at %s", order.Status, order.FilledAvgPrice)
+	}
+	positions, _ := broker.GetOpenPositions()
+	if len(positions) != 0 {
+		t.Errorf("Expected no open positions, got %d", len(positions))
+	}
+	cash, _ := broker.Cash()
+	if cash != 10000-40 {
+		t.Errorf("Expected cash 9960, got %v", cash)
+	}
+}
+
+func TestSimRejectsOrderWithoutCash(t *testing.T) {
+	broker, _ := newMockSim(100)
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(10)})
+	order, _ := broker.GetOrder(id)
+	if order.Status != Rejected {
+		t.Errorf("Expected order to be rejected, got %s", order.Status)
+	}
+}
+
+func TestSimRejectsSellWithoutPosition(t *testing.T) {
+	broker, _ := newMockSim(10000)
+
+	id, _ := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Sell, Type: Market, Qty: decimal.NewFromInt(10)})
+	order, _ := broker.GetOrder(id)
+	if order.Status != Rejected {
+		t.Errorf("Expected order to be rejected, got %s", order.Status)
+	}
+
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(10)})
+	id, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Sell, Type: Market, Qty: decimal.NewFromInt(11)})
+	order, _ = broker.GetOrder(id)
+	if order.Status != Rejected {
+		t.Errorf("Expected sell exceeding the position to be rejected, got %s", order.Status)
+	}
+	positions, _ := broker.GetOpenPositions()
+	if len(positions) != 1 || positions[0].Quantity != 10 {
+		t.Errorf("Unexpected positions: %+v", positions)
+	}
+	cash, _ := broker.Cash()
+	if cash != 10000-1010 {
+		t.Errorf("Expected cash 8990, got %v", cash)
+	}
+}
+
+func TestSimCancelAllOrders(t *testing.T) {
+	broker, _ := newMockSim(10000)
+
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(1), LimitPrice: decimal.NewFromInt(90)})
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(1), LimitPrice: decimal.NewFromInt(80)})
+	if err := broker.CancelAllOrders(); err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	open, _ := broker.GetOpenOrders()
+	if len(open) != 0 {
+		t.Errorf("Expected no open orders, got %d", len(open))
+	}
+}
+
+func TestSimEquity(t *testing.T) {
+	broker, provider := newMockSim(10000)
+
+	_, _ = broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market, Qty: decimal.NewFromInt(10)})
+	provider.setBar("AAPL", 101, 111, 100, 110)
+	_ = broker.ProcessBars()
+
+	equity, _ := broker.Equity()
+	if equity != 10090 {
+		t.Errorf("Expected equity 10090, got %v", equity)
+	}
+}
+
+func TestSimInvalidOrder(t *testing.T) {
+	broker, _ := newMockSim(10000)
+
+	if _, err := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Limit, Qty: decimal.NewFromInt(1)}); err == nil {
+		t.Errorf("Want error for limit order without price")
+	}
+	if _, err := broker.PlaceOrder(Order{Symbol: "AAPL", Side: Buy, Type: Market}); err == nil {
+		t.Errorf("Want error for zero quantity")
+	}
+}
@@ -0,0 +1,228 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
+)
+
+// ExitRule decides on the close of the last bar whether an open position has to be closed.
+type ExitRule interface {
+	Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error)
+	Name() string
+}
+
+// PositionState is the bookkeeping the trading system keeps for every open position.
+// EntryTime is the time the position was opened as reported by the broker or, if the broker
+// does not report it, the time of the last bar when the position was first seen. HighestPrice
+// and LowestPrice are the extreme prices since the position was first seen, starting at the
+// entry price.
+type PositionState struct {
+	brokers.Position
+	EntryTime    time.Time
+	HighestPrice float64
+	LowestPrice  float64
+}
+
+func newPositionState(p brokers.Position) *PositionState {
+	return &PositionState{
+		Position:     p,
+		EntryTime:    p.OpenedAt,
+		HighestPrice: p.AvgFillPrice,
+		LowestPrice:  p.AvgFillPrice,
+	}
+}
+
+// IsLong reports whether the position is a long position.
+func (pos *PositionState) IsLong() bool {
+	return pos.Quantity > 0
+}
+
+// update accounts for the last bar of the history.
+func (pos *PositionState) update(bars *indicators.BarHistory) {
+	if !pos.OpenedAt.IsZero() {
+		pos.EntryTime = pos.OpenedAt
+	}
+	if bars == nil || bars.Len() == 0 {
+		return
+	}
+	last := bars.Len() - 1
+	if pos.EntryTime.IsZero() && bars.HasTime() {
+		pos.EntryTime = bars.Time[last]
+	}
+	pos.HighestPrice = math.Max(pos.HighestPrice, bars.High[last])
+	pos.LowestPrice = math.Min(pos.LowestPrice, bars.Low[last])
+}
+
+// BarsHeld returns the number of bars of the history starting after the entry time. If the
+// entry lies before the first bar, the length of the history is returned as a lower bound.
+func (pos *PositionState) BarsHeld(bars *indicators.BarHistory) (int, error) {
+	if pos.EntryTime.IsZero() {
+		return 0, fmt.Errorf("unknown entry time of %s", pos.Symbol)
+	}
+	if !bars.HasTime() {
+		return 0, fmt.Errorf("bars without timestamps")
+	}
+	i := sort.Search(bars.Len(), func(i int) bool { return bars.Time[i].After(pos.EntryTime) })
+	return bars.Len() - i, nil
+}
+
+// PercentStop closes a position once the close has moved Percent against the entry price.
+type PercentStop struct {
+	Percent float64
+}
+
+func (r PercentStop) Name() string { return fmt.Sprintf("%v%% stop loss", r.Percent) }
+
+func (r PercentStop) Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error) {
+	if r.Percent <= 0 {
+		return false, fmt.Errorf("invalid percent: %v", r.Percent)
+	}
+	price, err := lastClose(bars)
+	if err != nil {
+		return false, err
+	}
+	return stopHit(pos, price, pos.AvgFillPrice*r.Percent/100), nil
+}
+
+// ATRStop closes a position once the close has moved Multiple times the current
+// average true range against the entry price.
+type ATRStop struct {
+	Period   int
+	Multiple float64
+}
+
+func (r ATRStop) Name() string { return fmt.Sprintf("%v ATR(%d) stop loss", r.Multiple, r.Period) }
+
+func (r ATRStop) Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error) {
+	if r.Multiple <= 0 {
+		return false, fmt.Errorf("invalid multiple: %v", r.Multiple)
+	}
+	atr, err := lastATR(bars, r.Period)
+	if err != nil {
+		return false, err
+	}
+	price, err := lastClose(bars)
+	if err != nil {
+		return false, err
+	}
+	return stopHit(pos, price, r.Multiple*atr), nil
+}
+
+// ProfitTarget closes a position once the close has moved Percent in favour of the entry price.
+type ProfitTarget struct {
+	Percent float64
+}
+
+func (r ProfitTarget) Name() string { return fmt.Sprintf("%v%% profit target", r.Percent) }
+
+func (r ProfitTarget) Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error) {
+	if r.Percent <= 0 {
+		return false, fmt.Errorf("invalid percent: %v", r.Percent)
+	}
+	price, err := lastClose(bars)
+	if err != nil {
+		return false, err
+	}
+	distance := pos.AvgFillPrice * r.Percent / 100
+	if pos.IsLong() {
+		return price >= pos.AvgFillPrice+distance, nil
+	}
+	return price <= pos.AvgFillPrice-distance, nil
+}
+
+// TrailingStop closes a position once the close has retraced Percent from the most
+// favourable price since entry. If Multiple is set, the distance is Multiple times the
+// current ATR(Period) instead.
+type TrailingStop struct {
+	Percent  float64
+	Period   int
+	Multiple float64
+}
+
+func (r TrailingStop) Name() string {
+	if r.Multiple > 0 {
+		return fmt.Sprintf("%v ATR(%d) trailing stop", r.Multiple, r.Period)
+	}
+	return fmt.Sprintf("%v%% trailing stop", r.Percent)
+}
+
+func (r TrailingStop) Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error) {
+	price, err := lastClose(bars)
+	if err != nil {
+		return false, err
+	}
+	extreme := pos.HighestPrice
+	if !pos.IsLong() {
+		extreme = pos.LowestPrice
+	}
+
+	var distance float64
+	switch {
+	case r.Multiple > 0:
+		atr, err := lastATR(bars, r.Period)
+		if err != nil {
+			return false, err
+		}
+		distance = r.Multiple * atr
+	case r.Percent > 0:
+		distance = extreme * r.Percent / 100
+	default:
+		return false, fmt.Errorf("invalid trailing stop: neither percent nor ATR multiple set")
+	}
+
+	if pos.IsLong() {
+		return price <= extreme-distance, nil
+	}
+	return price >= extreme+distance, nil
+}
+
+// MaxHoldingDays closes a position once Days bars have started after the entry. The bars need
+// timestamps and the history has to reach back to the entry or contain at least Days bars.
+type MaxHoldingDays struct {
+	Days int
+}
+
+func (r MaxHoldingDays) Name() string { return fmt.Sprintf("%d days time exit", r.Days) }
+
+func (r MaxHoldingDays) Exit(pos *PositionState, bars *indicators.BarHistory) (bool, error) {
+	if r.Days <= 0 {
+		return false, fmt.Errorf("invalid number of days: %d", r.Days)
+	}
+	held, err := pos.BarsHeld(bars)
+	if err != nil {
+		return false, err
+	}
+	if held < r.Days && held == bars.Len() {
+		return false, fmt.Errorf("history of %d bars does not reach back to the entry at %v", bars.Len(), pos.EntryTime)
+	}
+	return held >= r.Days, nil
+}
+
+// stopHit reports whether the price has moved the given distance against the entry price.
+func stopHit(pos *PositionState, price, distance float64) bool {
+	if pos.IsLong() {
+		return price <= pos.AvgFillPrice-distance
+	}
+	return price >= pos.AvgFillPrice+distance
+}
+
+func lastClose(bars *indicators.BarHistory) (float64, error) {
+	return indicators.Latest(indicators.Last(*bars, indicators.Close))
+}
+
+// isPositive reports whether x is a finite number greater than 0.
+func isPositive(x float64) bool {
+	return x > 0 && !math.IsInf(x, 1)
+}
+
+func lastATR(bars *indicators.BarHistory, period int) (float64, error) {
+	if period <= 0 || bars.Len() <= period {
+		return 0, fmt.Errorf("invalid ATR period: %d for %d bars", period, bars.Len())
+	}
+	return indicators.Latest(indicators.ATR(*bars, period))
+}
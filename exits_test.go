@@ -0,0 +1,168 @@
+package gofin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+)
+
+func longPosition(entry float64) *PositionState {
+	return newPositionState(brokers.Position{Symbol: "A", Quantity: 10, AvgFillPrice: entry})
+}
+
+func shortPosition(entry float64) *PositionState {
+	return newPositionState(brokers.Position{Symbol: "A", Quantity: -10, AvgFillPrice: entry})
+}
+
+func TestPercentStop(t *testing.T) {
+	rule := PercentStop{Percent: 5}
+
+	exit, _ := rule.Exit(longPosition(100), testHistory(100, 96))
+	if exit {
+		t.Errorf("Expected no exit above stop")
+	}
+	exit, _ = rule.Exit(longPosition(100), testHistory(100, 95))
+	if !exit {
+		t.Errorf("Expected exit at stop")
+	}
+	exit, _ = rule.Exit(shortPosition(100), testHistory(100, 106))
+	if !exit {
+		t.Errorf("Expected exit of short position")
+	}
+	if _, err := (PercentStop{}).Exit(longPosition(100), testHistory(100)); err == nil {
+		t.Errorf("Want error for invalid percent")
+	}
+}
+
+func TestATRStop(t *testing.T) {
+	// true range is 2 for every bar but the last
+	rule := ATRStop{Period: 3, Multiple: 2}
+
+	exit, _ := rule.Exit(longPosition(100), testHistory(100, 100, 100, 100, 97))
+	if exit {
+		t.Errorf("Expected no exit above stop")
+	}
+	// true range 11 gives ATR 5
+	exit, _ = rule.Exit(longPosition(100), testHistory(100, 100, 100, 100, 90))
+	if !exit {
+		t.Errorf("Expected exit at stop")
+	}
+	if _, err := (ATRStop{Period: 10, Multiple: 2}).Exit(longPosition(100), testHistory(100, 96)); err == nil {
+		t.Errorf("Want error for too short history")
+	}
+	for _, multiple := range []float64{0, -2} {
+		if _, err := (ATRStop{Period: 3, Multiple: multiple}).Exit(longPosition(100), testHistory(100, 100, 100, 100, 97)); err == nil {
+			t.Errorf("Want error for multiple %v", multiple)
+		}
+	}
+}
+
+func TestProfitTarget(t *testing.T) {
+	rule := ProfitTarget{Percent: 10}
+
+	exit, _ := rule.Exit(longPosition(100), testHistory(100, 109))
+	if exit {
+		t.Errorf("Expected no exit below target")
+	}
+	exit, _ = rule.Exit(longPosition(100), testHistory(100, 110))
+	if !exit {
+		t.Errorf("Expected exit at target")
+	}
+	exit, _ = rule.Exit(shortPosition(100), testHistory(100, 90))
+	if !exit {
+		t.Errorf("Expected exit of short position")
+	}
+}
+
+func TestTrailingStop(t *testing.T) {
+	rule := TrailingStop{Percent: 10}
+	pos := longPosition(100)
+
+	for _, c := range []float64{110, 119} {
+		bars := testHistory(c)
+		pos.update(bars)
+		if exit, _ := rule.Exit(pos, bars); exit {
+			t.Fatalf("Expected no exit at %v", c)
+		}
+	}
+	// highest high is 120
+	bars := testHistory(108)
+	pos.update(bars)
+	if exit, _ := rule.Exit(pos, bars); !exit {
+		t.Errorf("Expected exit after retracement, highest price %v", pos.HighestPrice)
+	}
+	if _, err := (TrailingStop{}).Exit(pos, bars); err == nil {
+		t.Errorf("Want error for unset trailing stop")
+	}
+}
+
+func TestMaxHoldingDays(t *testing.T) {
+	rule := MaxHoldingDays{Days: 2}
+	bars := datedHistory(100, 101, 102, 103)
+	pos := longPosition(100)
+	pos.OpenedAt = bars.Time[1].Add(10 * time.Hour)
+
+	// checking the position several times a day does not extend the holding period
+	for _, end := range []int{2, 2, 3, 3} {
+		sub := bars.Sub(0, end)
+		pos.update(&sub)
+		if exit, err := rule.Exit(pos, &sub); err != nil || exit {
+			t.Fatalf("Expected no exit after %d bars, got %v, %v", end, exit, err)
+		}
+	}
+	pos.update(bars)
+	if exit, err := rule.Exit(pos, bars); err != nil || !exit {
+		t.Errorf("Expected exit after 2 days, got %v, %v", exit, err)
+	}
+
+	// the entry lies before the history
+	short := bars.Sub(3, 4)
+	if _, err := rule.Exit(pos, &short); err == nil {
+		t.Errorf("Want error for history not reaching back to the entry")
+	}
+	if exit, err := (MaxHoldingDays{Days: 1}).Exit(pos, &short); err != nil || !exit {
+		t.Errorf("Expected exit for history longer than the holding period, got %v, %v", exit, err)
+	}
+
+	// without timestamps the holding period is unknown
+	bars.Time = nil
+	if _, err := rule.Exit(pos, bars); err == nil {
+		t.Errorf("Want error for bars without timestamps")
+	}
+}
+
+func TestBacktestWithExitRule(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"A": datedHistory(10, 10, 10, 11, 12, 13, 14, 15, 16, 17),
+	}
+	watchlist := utils.NewWatchlist(
+		[]utils.Asset{{Symbol: "A"}},
+		[]utils.Filter{*utils.NewFilter(indicators.ROC(nil, 1), utils.GT, 0.0)},
+		nil,
+	)
+	bt, err := NewBacktest(watchlist, history, 1000, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	bt.SetLookback(2)
+	bt.System().AddExitRule(MaxHoldingDays{Days: 2})
+
+	res, err := bt.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// enter on bar 3, exit on bar 5, re-enter on bar 6 as the exit bar is skipped, exit on bar 8
+	if len(res.Trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %+v", res.Trades)
+	}
+	trade := res.Trades[0]
+	if trade.EntryBar != 3 || trade.ExitBar != 5 || trade.EntryPrice != 11 || trade.ExitPrice != 13 {
+		t.Errorf("Unexpected trade: %+v", trade)
+	}
+	if trade = res.Trades[1]; trade.EntryBar != 6 || trade.ExitBar != 8 {
+		t.Errorf("Unexpected trade: %+v", trade)
+	}
+}
@@ -0,0 +1,90 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the Aroon indicator.
+const (
+	AroonUp         = "up"
+	AroonDown       = "down"
+	AroonOscillator = "oscillator"
+)
+
+// AroonLines holds the outputs of the Aroon indicator.
+type AroonLines struct {
+	Up         []float64
+	Down       []float64
+	Oscillator []float64
+}
+
+// AroonIndicator measures the time since the highest high and the lowest low of the last
+// Period+1 bars. Up is 100 on a new high and falls to 0 if the high is Period bars old, Down
+// does the same for the low. The oscillator is Up minus Down. If an extreme occurs several
+// times the most recent one counts. The first Period values are NaN.
+type AroonIndicator struct {
+	BarHistoryIndicator
+}
+
+func Aroon(bars BarHistory, period int) *AroonIndicator {
+	return &AroonIndicator{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+// Compute returns the oscillator.
+func (ind *AroonIndicator) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.Oscillator, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *AroonIndicator) Outputs() []string {
+	return []string{AroonUp, AroonDown, AroonOscillator}
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *AroonIndicator) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{AroonUp: res.Up, AroonDown: res.Down, AroonOscillator: res.Oscillator}, nil
+}
+
+// ComputeLines returns up, down and the oscillator.
+func (ind *AroonIndicator) ComputeLines() (AroonLines, error) {
+	err := CheckInput(ind.Input.High, ind.Period)
+	if err != nil {
+		return AroonLines{}, err
+	}
+	if len(ind.Input.Low) != len(ind.Input.High) {
+		return AroonLines{}, fmt.Errorf("Aroon requires high and low of every bar")
+	}
+
+	n := len(ind.Input.High)
+	res := AroonLines{Up: make([]float64, n), Down: make([]float64, n), Oscillator: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		if i < ind.Period {
+			res.Up[i], res.Down[i], res.Oscillator[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		high, low := i, i
+		for j := i - 1; j >= i-ind.Period; j-- {
+			if ind.Input.High[j] > ind.Input.High[high] {
+				high = j
+			}
+			if ind.Input.Low[j] < ind.Input.Low[low] {
+				low = j
+			}
+		}
+		res.Up[i] = 100 * float64(ind.Period-(i-high)) / float64(ind.Period)
+		res.Down[i] = 100 * float64(ind.Period-(i-low)) / float64(ind.Period)
+		res.Oscillator[i] = res.Up[i] - res.Down[i]
+	}
+	return res, nil
+}
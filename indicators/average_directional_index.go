@@ -1,11 +1,28 @@
 package indicators
 
 import (
+	"fmt"
 	"math"
 )
 
+// Names of the outputs of the ADX.
+const (
+	ADXLine = "adx"
+	DIPlus  = "+DI"
+	DIMinus = "-DI"
+)
+
 type AverageDirectionalIndex struct {
 	BarHistoryIndicator
+
+	count    int
+	previous Bar
+	tr       TrueRange
+	pdp      float64
+	pdm      float64
+	ptr      float64
+	dx       float64
+	adx      float64
 }
 
 func ADX(bars BarHistory, period int) *AverageDirectionalIndex {
@@ -15,12 +32,36 @@ func ADX(bars BarHistory, period int) *AverageDirectionalIndex {
 }
 
 // Calculate Average Directional Index (ADX)
-func (ind *AverageDirectionalIndex) Compute() []float64 {
-	if len(ind.Input.Close) < ind.Period {
-		return nil
+func (ind *AverageDirectionalIndex) Compute() ([]float64, error) {
+	adip, adim, err := ind.DiPlusMinus()
+	if err != nil {
+		return nil, err
 	}
+	return ind.adxLine(adip, adim)
+}
 
-	adip, adim := ind.DiPlusMinus()
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *AverageDirectionalIndex) Outputs() []string {
+	return []string{ADXLine, DIPlus, DIMinus}
+}
+
+// ComputeAll returns the ADX and the directional indicators by output name.
+func (ind *AverageDirectionalIndex) ComputeAll() (map[string][]float64, error) {
+	adip, adim, err := ind.DiPlusMinus()
+	if err != nil {
+		return nil, err
+	}
+	adx, err := ind.adxLine(adip, adim)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{ADXLine: adx, DIPlus: adip, DIMinus: adim}, nil
+}
+
+func (ind *AverageDirectionalIndex) adxLine(adip, adim []float64) ([]float64, error) {
+	if len(ind.Input.Close) < 2*ind.Period {
+		return nil, fmt.Errorf("ADX(%d) requires at least %d bars", ind.Period, 2*ind.Period)
+	}
 
 	dx := 0.0
 	for i := ind.Period; i < 2*ind.Period; i++ {
@@ -29,23 +70,32 @@ func (ind *AverageDirectionalIndex) Compute() []float64 {
 	dx /= float64(ind.Period)
 
 	adx := make([]float64, len(ind.Input.Close))
+	for i := 0; i < 2*ind.Period-1; i++ {
+		adx[i] = math.NaN()
+	}
 	adx[2*ind.Period-1] = dx
 	for i := 2 * ind.Period; i < len(ind.Input.Close); i++ {
 		adx[i] = (adx[i-1]*float64(ind.Period-1) + 100*math.Abs(adip[i]-adim[i])/(adip[i]+adim[i])) / float64(ind.Period)
 	}
 
-	return adx
+	return adx, nil
 }
 
-func (ind *AverageDirectionalIndex) DiPlusMinus() ([]float64, []float64) {
-	if len(ind.Input.Close) < ind.Period {
-		return nil, nil
+// DiPlusMinus returns the positive and negative directional indicators +DI and -DI, both
+// are NaN for the first Period values.
+func (ind *AverageDirectionalIndex) DiPlusMinus() (plus, minus []float64, err error) {
+	err = CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	dp := make([]float64, len(ind.Input.Close))
 	dm := make([]float64, len(ind.Input.Close))
 
-	tr := TR(ind.Input).Compute()
+	tr, err := TR(ind.Input).Compute()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	for i := 1; i < len(ind.Input.Close); i++ {
 
@@ -88,7 +138,67 @@ func (ind *AverageDirectionalIndex) DiPlusMinus() ([]float64, []float64) {
 		pdm = adm
 		ptr = atr
 	}
+	for i := 0; i < ind.Period; i++ {
+		dp[i] = math.NaN()
+		dm[i] = math.NaN()
+	}
 
-	return dp, dm
+	return dp, dm, nil
+
+}
+
+// Update adds the bar and returns the current ADX.
+func (ind *AverageDirectionalIndex) Update(bar Bar) float64 {
+	i := ind.count
+	ind.count++
+	defer func() { ind.previous = bar }()
+
+	dp, dm := 0.0, 0.0
+	if i > 0 {
+		upMove := bar.High - ind.previous.High
+		downMove := ind.previous.Low - bar.Low
+		if upMove > downMove && upMove > 0 {
+			dp = upMove
+		}
+		if downMove > upMove && downMove > 0 {
+			dm = downMove
+		}
+	}
+	tr := ind.tr.Update(bar)
+
+	if i < ind.Period {
+		ind.pdp += dp
+		ind.pdm += dm
+		ind.ptr += tr
+		return math.NaN()
+	}
+
+	period := float64(ind.Period)
+	ind.pdp = ind.pdp - ind.pdp/period + dp
+	ind.pdm = ind.pdm - ind.pdm/period + dm
+	ind.ptr = ind.ptr - ind.ptr/period + tr
+	dip := math.Round(100.0 * ind.pdp / ind.ptr)
+	dim := math.Round(100.0 * ind.pdm / ind.ptr)
+	dx := 100 * math.Abs(dip-dim) / (dip + dim)
+
+	switch {
+	case i < 2*ind.Period-1:
+		ind.dx += dx
+		return math.NaN()
+	case i == 2*ind.Period-1:
+		ind.dx += dx
+		ind.adx = ind.dx / period
+	default:
+		ind.adx = (ind.adx*float64(ind.Period-1) + dx) / period
+	}
+	return ind.adx
+}
 
+// Reset clears the state of Update.
+func (ind *AverageDirectionalIndex) Reset() {
+	ind.count = 0
+	ind.previous = Bar{}
+	ind.tr.Reset()
+	ind.pdp, ind.pdm, ind.ptr = 0, 0, 0
+	ind.dx, ind.adx = 0, 0
 }
@@ -1,11 +1,15 @@
 package indicators
 
 import (
+	"errors"
 	"math"
 )
 
 type AverageTrueRange struct {
 	BarHistoryIndicator
+
+	tr TrueRange
+	ma *MovingAverage
 }
 
 func ATR(bars BarHistory, period int) *AverageTrueRange {
@@ -16,6 +20,9 @@ func ATR(bars BarHistory, period int) *AverageTrueRange {
 
 type TrueRange struct {
 	BarHistoryIndicator
+
+	count     int
+	prevClose float64
 }
 
 func TR(bars BarHistory) *TrueRange {
@@ -26,6 +33,8 @@ func TR(bars BarHistory) *TrueRange {
 
 type TrueRangePercent struct {
 	BarHistoryIndicator
+
+	atr *AverageTrueRange
 }
 
 func ATRP(bars BarHistory, period int) *TrueRangePercent {
@@ -34,26 +43,34 @@ func ATRP(bars BarHistory, period int) *TrueRangePercent {
 	}
 }
 
-func (ind *AverageTrueRange) Compute() []float64 {
-	if len(ind.Input.Close) < ind.Period || len(ind.Input.High) < ind.Period || len(ind.Input.Low) < ind.Period {
-		return nil
+func (ind *AverageTrueRange) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
 	}
-	tr := TR(ind.Input)
-	trueRange := tr.Compute()
-	atr, _ := MA(trueRange, ind.Period).Compute(WILDER)
-	return atr
+	trueRange, err := TR(ind.Input).Compute()
+	if err != nil {
+		return nil, err
+	}
+	return MA(trueRange, ind.Period).WithType(WILDER).Compute()
 }
 
-func (ind *TrueRangePercent) Compute() []float64 {
-	atr := ATR(ind.Input, ind.Period).Compute()
+func (ind *TrueRangePercent) Compute() ([]float64, error) {
+	atr, err := ATR(ind.Input, ind.Period).Compute()
+	if err != nil {
+		return nil, err
+	}
 	res := make([]float64, len(atr))
 	for i, val := range atr {
 		res[i] = val / ind.Input.Close[i] * 100
 	}
-	return res
+	return res, nil
 }
 
-func (ind *TrueRange) Compute() []float64 {
+func (ind *TrueRange) Compute() ([]float64, error) {
+	if len(ind.Input.Close) == 0 || len(ind.Input.High) != len(ind.Input.Close) || len(ind.Input.Low) != len(ind.Input.Close) {
+		return nil, errors.New("true range requires high, low and close of every bar")
+	}
 	tr := make([]float64, len(ind.Input.Close))
 	tr[0] = ind.Input.High[0] - ind.Input.Low[0]
 	for i := 1; i < len(ind.Input.Close); i++ {
@@ -62,5 +79,52 @@ func (ind *TrueRange) Compute() []float64 {
 		lowClose := math.Abs(ind.Input.Low[i] - ind.Input.Close[i-1])
 		tr[i] = math.Max(highLow, math.Max(highClose, lowClose))
 	}
-	return tr
+	return tr, nil
+}
+
+// Update adds the bar and returns its true range.
+func (ind *TrueRange) Update(bar Bar) float64 {
+	ind.count++
+	defer func() { ind.prevClose = bar.Close }()
+
+	highLow := bar.High - bar.Low
+	if ind.count == 1 {
+		return highLow
+	}
+	highClose := math.Abs(bar.High - ind.prevClose)
+	lowClose := math.Abs(bar.Low - ind.prevClose)
+	return math.Max(highLow, math.Max(highClose, lowClose))
+}
+
+// Reset clears the state of Update.
+func (ind *TrueRange) Reset() {
+	ind.count = 0
+	ind.prevClose = 0
+}
+
+// Update adds the bar and returns the current ATR.
+func (ind *AverageTrueRange) Update(bar Bar) float64 {
+	if ind.ma == nil {
+		ind.ma = MA(nil, ind.Period).WithType(WILDER)
+	}
+	return ind.ma.UpdateValue(ind.tr.Update(bar))
+}
+
+// Reset clears the state of Update.
+func (ind *AverageTrueRange) Reset() {
+	ind.tr.Reset()
+	ind.ma = nil
+}
+
+// Update adds the bar and returns the current ATR in percent of the close.
+func (ind *TrueRangePercent) Update(bar Bar) float64 {
+	if ind.atr == nil {
+		ind.atr = ATR(BarHistory{}, ind.Period)
+	}
+	return ind.atr.Update(bar) / bar.Close * 100
+}
+
+// Reset clears the state of Update.
+func (ind *TrueRangePercent) Reset() {
+	ind.atr = nil
 }
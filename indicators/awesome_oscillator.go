@@ -0,0 +1,48 @@
+package indicators
+
+import "fmt"
+
+// AwesomeOscillator is the difference of a fast and a slow simple moving average of the
+// median price (high+low)/2. The first Slow-1 values are NaN.
+type AwesomeOscillator struct {
+	BarHistoryIndicator
+	Fast int
+	Slow int
+}
+
+// AO creates the Awesome Oscillator, the common periods are 5 and 34.
+func AO(bars BarHistory, fast, slow int) *AwesomeOscillator {
+	return &AwesomeOscillator{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, slow),
+		Fast:                fast,
+		Slow:                slow,
+	}
+}
+
+func (ind *AwesomeOscillator) Compute() ([]float64, error) {
+	if ind.Fast <= 0 || ind.Slow <= ind.Fast {
+		return nil, fmt.Errorf("invalid periods: fast %d, slow %d", ind.Fast, ind.Slow)
+	}
+	if len(ind.Input.Low) != len(ind.Input.High) {
+		return nil, fmt.Errorf("Awesome Oscillator requires high and low of every bar")
+	}
+
+	median := make([]float64, len(ind.Input.High))
+	for i := range median {
+		median[i] = (ind.Input.High[i] + ind.Input.Low[i]) / 2
+	}
+	fast, err := MA(median, ind.Fast).Compute()
+	if err != nil {
+		return nil, err
+	}
+	slow, err := MA(median, ind.Slow).Compute()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(median))
+	for i := range res {
+		res[i] = fast[i] - slow[i]
+	}
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package indicators
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Len returns the number of bars.
+func (bars *BarHistory) Len() int {
+	return len(bars.Close)
+}
+
+// HasTime reports whether every bar carries a timestamp.
+func (bars *BarHistory) HasTime() bool {
+	return len(bars.Time) > 0 && len(bars.Time) == len(bars.Close)
+}
+
+// Sub returns the bars with index in [start, end). The columns share memory with bars.
+// Columns that are not populated stay empty.
+func (bars *BarHistory) Sub(start, end int) BarHistory {
+	return BarHistory{
+		Time:   window(bars.Time, start, end),
+		Open:   window(bars.Open, start, end),
+		High:   window(bars.High, start, end),
+		Low:    window(bars.Low, start, end),
+		Close:  window(bars.Close, start, end),
+		Volume: window(bars.Volume, start, end),
+	}
+}
+
+// Between returns the bars with a timestamp in the closed interval [from, to].
+func (bars *BarHistory) Between(from, to time.Time) (BarHistory, error) {
+	if !bars.HasTime() {
+		return BarHistory{}, fmt.Errorf("bar history has no timestamps")
+	}
+	start := sort.Search(len(bars.Time), func(i int) bool { return !bars.Time[i].Before(from) })
+	end := sort.Search(len(bars.Time), func(i int) bool { return bars.Time[i].After(to) })
+	if end < start {
+		end = start
+	}
+	return bars.Sub(start, end), nil
+}
+
+// IndexOf returns the index of the bar with the given timestamp or -1.
+func (bars *BarHistory) IndexOf(t time.Time) int {
+	i := sort.Search(len(bars.Time), func(i int) bool { return !bars.Time[i].Before(t) })
+	if i < len(bars.Time) && bars.Time[i].Equal(t) {
+		return i
+	}
+	return -1
+}
+
+// AlignHistories restricts all histories to the timestamps they have in common,
+// so that the same index refers to the same bar in every returned history.
+func AlignHistories(histories map[string]*BarHistory) (map[string]*BarHistory, error) {
+	var common map[int64]int
+	for symbol, bars := range histories {
+		if !bars.HasTime() {
+			return nil, fmt.Errorf("bar history of %s has no timestamps", symbol)
+		}
+		seen := make(map[int64]int, len(bars.Time))
+		for _, t := range bars.Time {
+			key := t.UnixNano()
+			if common == nil || common[key] > 0 {
+				seen[key] = 1
+			}
+		}
+		common = seen
+	}
+
+	res := make(map[string]*BarHistory, len(histories))
+	for symbol, bars := range histories {
+		aligned := &BarHistory{}
+		for i, t := range bars.Time {
+			if common[t.UnixNano()] == 0 {
+				continue
+			}
+			aligned.Time = append(aligned.Time, t)
+			aligned.Close = append(aligned.Close, bars.Close[i])
+			// columns that are not populated stay empty
+			if len(bars.Open) == bars.Len() {
+				aligned.Open = append(aligned.Open, bars.Open[i])
+			}
+			if len(bars.High) == bars.Len() {
+				aligned.High = append(aligned.High, bars.High[i])
+			}
+			if len(bars.Low) == bars.Len() {
+				aligned.Low = append(aligned.Low, bars.Low[i])
+			}
+			if len(bars.Volume) == bars.Len() {
+				aligned.Volume = append(aligned.Volume, bars.Volume[i])
+			}
+		}
+		res[symbol] = aligned
+	}
+	return res, nil
+}
+
+func window[T any](s []T, start, end int) []T {
+	if len(s) < end {
+		return nil
+	}
+	return s[start:end]
+}
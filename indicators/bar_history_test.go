@@ -0,0 +1,83 @@
+package indicators
+
+import "testing"
+
+func TestBarHistorySub(t *testing.T) {
+	got := TestBars.Sub(2, 5)
+	if got.Len() != 3 || got.Close[0] != TestBars.Close[2] || got.Volume[2] != TestBars.Volume[4] {
+		t.Fatalf("Unexpected sub history: %v", got.Close)
+	}
+	if got.Time != nil {
+		t.Errorf("Expected no timestamps, got %v", got.Time)
+	}
+}
+
+func TestBarHistoryBetween(t *testing.T) {
+	bars := datedBars([]int{12, 13, 14, 15, 16}, []float64{1, 2, 3, 4, 5})
+
+	got, err := bars.Between(day(13), day(15))
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	_, err = sliceAlmostEqual(got.Close, []float64{2, 3, 4}, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ = bars.Between(day(17), day(20))
+	if got.Len() != 0 {
+		t.Errorf("Expected empty history, got %v", got.Close)
+	}
+
+	_, err = TestBars.Between(day(13), day(15))
+	if err == nil {
+		t.Errorf("Want error for missing timestamps")
+	}
+}
+
+func TestBarHistoryIndexOf(t *testing.T) {
+	bars := datedBars([]int{12, 13, 15}, []float64{1, 2, 3})
+	if i := bars.IndexOf(day(15)); i != 2 {
+		t.Errorf("Expected index 2, got %d", i)
+	}
+	if i := bars.IndexOf(day(14)); i != -1 {
+		t.Errorf("Expected index -1, got %d", i)
+	}
+}
+
+func TestAlignHistories(t *testing.T) {
+	histories := map[string]*BarHistory{
+		"A": datedBars([]int{12, 13, 14, 15}, []float64{1, 2, 3, 4}),
+		"B": datedBars([]int{13, 15, 16}, []float64{20, 40, 50}),
+	}
+	got, err := AlignHistories(histories)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	_, err = sliceAlmostEqual(got["A"].Close, []float64{2, 4}, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sliceAlmostEqual(got["B"].Close, []float64{20, 40}, 1e-9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got["A"].Time[1].Equal(got["B"].Time[1]) {
+		t.Errorf("Expected equal timestamps, got %v and %v", got["A"].Time[1], got["B"].Time[1])
+	}
+
+	// columns that are not populated stay empty
+	closeOnly := &BarHistory{Time: histories["B"].Time, Close: histories["B"].Close}
+	got, err = AlignHistories(map[string]*BarHistory{"A": histories["A"], "B": closeOnly})
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if got["B"].Len() != 2 || got["B"].Open != nil || got["B"].Volume != nil || len(got["A"].Open) != 2 {
+		t.Errorf("Unexpected aligned histories: %+v, %+v", got["A"], got["B"])
+	}
+
+	_, err = AlignHistories(map[string]*BarHistory{"C": &TestBars})
+	if err == nil {
+		t.Errorf("Want error for missing timestamps")
+	}
+}
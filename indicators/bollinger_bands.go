@@ -2,50 +2,173 @@ package indicators
 
 import (
 	"fmt"
+	"math"
 )
 
+// Names of the outputs of the Bollinger Bands.
+const (
+	BBUpper     = "upper"
+	BBMean      = "mean"
+	BBLower     = "lower"
+	BBBandwidth = "bandwidth"
+	BBPercentB  = "%B"
+)
+
+// BBands holds the bands of the Bollinger Bands. BandWidth is the distance between the
+// upper and the lower band and PercentB the position of the input within the bands, 0 at
+// the lower and 1 at the upper band. If the bands collapse to zero width, PercentB is 0.5.
 type BBands struct {
-	upper      []float64
-	mean       []float64
-	lower      []float64
-	band_width []float64
+	Upper     []float64
+	Mean      []float64
+	Lower     []float64
+	BandWidth []float64
+	PercentB  []float64
 }
 
 type BollingerBands struct {
 	TimeSeriesIndicator
+	// Factor and Type are the band width in standard deviations and the moving average
+	// used by Update, they default to 2 and SMA.
+	Factor float64
+	Type   maType
+
+	ma     *MovingAverage
+	window ringBuffer
+	upper  float64
+	mean   float64
+	lower  float64
 }
 
 func BB(input []float64, period int) *BollingerBands {
 	return &BollingerBands{
 		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+		Factor:              2,
+	}
+}
+
+// WithFactor sets the band width in standard deviations.
+func (ind *BollingerBands) WithFactor(factor float64) *BollingerBands {
+	ind.Factor = factor
+	return ind
+}
+
+// WithType sets the type of the moving average of the middle band.
+func (ind *BollingerBands) WithType(matype maType) *BollingerBands {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns the middle band.
+func (ind *BollingerBands) Compute() ([]float64, error) {
+	res, err := ind.ComputeBands()
+	if err != nil {
+		return nil, err
 	}
+	return res.Mean, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *BollingerBands) Outputs() []string {
+	return []string{BBUpper, BBMean, BBLower, BBBandwidth, BBPercentB}
 }
 
-func (ind *BollingerBands) Compute(factor float64, method maType) (BBands, error) {
+// ComputeAll returns the bands by output name.
+func (ind *BollingerBands) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeBands()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{
+		BBUpper:     res.Upper,
+		BBMean:      res.Mean,
+		BBLower:     res.Lower,
+		BBBandwidth: res.BandWidth,
+		BBPercentB:  res.PercentB,
+	}, nil
+}
+
+// ComputeBands returns all bands. The upper and lower band, the band width and %B are NaN
+// for the first Period-1 values, the warm-up of the middle band depends on Type.
+func (ind *BollingerBands) ComputeBands() (BBands, error) {
 	err := CheckInput(ind.Input, ind.Period)
 	if err != nil {
 		return BBands{}, err
 	}
-	if factor < 0 {
-		return BBands{}, fmt.Errorf("invalid factor: %v", factor)
+	if ind.Factor < 0 {
+		return BBands{}, fmt.Errorf("invalid factor: %v", ind.Factor)
 	}
-	res := BBands{mean: []float64{}, lower: []float64{}, upper: []float64{}, band_width: []float64{}}
+	res := BBands{}
 
-	res.mean, err = MA(ind.Input, ind.Period).Compute(method)
+	res.Mean, err = MA(ind.Input, ind.Period).WithType(ind.Type).WithVolume(ind.Volume).Compute()
 	if err != nil {
 		return BBands{}, err
 	}
 
-	res.upper = make([]float64, len(ind.Input))
-	res.lower = make([]float64, len(ind.Input))
-	res.band_width = make([]float64, len(ind.Input))
+	res.Upper = make([]float64, len(ind.Input))
+	res.Lower = make([]float64, len(ind.Input))
+	res.BandWidth = make([]float64, len(ind.Input))
+	res.PercentB = make([]float64, len(ind.Input))
+	for i := 0; i < ind.Period-1; i++ {
+		res.Upper[i] = math.NaN()
+		res.Lower[i] = math.NaN()
+		res.BandWidth[i] = math.NaN()
+		res.PercentB[i] = math.NaN()
+	}
 	stddev := 0.0
 	for i := ind.Period - 1; i < len(ind.Input); i++ {
-		stddev = factor * StdDev(ind.Input[i-ind.Period+1:i+1])
-		res.upper[i] = res.mean[i] + stddev
-		res.lower[i] = res.mean[i] - stddev
-		res.band_width[i] = res.upper[i] - res.lower[i]
+		stddev = ind.Factor * StdDev(ind.Input[i-ind.Period+1:i+1])
+		res.Upper[i] = res.Mean[i] + stddev
+		res.Lower[i] = res.Mean[i] - stddev
+		res.BandWidth[i] = res.Upper[i] - res.Lower[i]
+		if res.BandWidth[i] == 0 {
+			res.PercentB[i] = 0.5
+		} else {
+			res.PercentB[i] = (ind.Input[i] - res.Lower[i]) / res.BandWidth[i]
+		}
 	}
 
 	return res, nil
 }
+
+// Update adds the close and the volume of the bar and returns the current middle band.
+func (ind *BollingerBands) Update(bar Bar) float64 {
+	return ind.update(bar.Close, float64(bar.Volume))
+}
+
+// UpdateValue adds the value and returns the current middle band. The other bands are
+// returned by Bands. A VWMA middle band weights all values equally, see
+// MovingAverage.UpdateValue.
+func (ind *BollingerBands) UpdateValue(value float64) float64 {
+	return ind.update(value, 1)
+}
+
+func (ind *BollingerBands) update(value, volume float64) float64 {
+	if ind.Period <= 0 {
+		return math.NaN()
+	}
+	if ind.ma == nil {
+		ind.ma = MA(nil, ind.Period).WithType(ind.Type)
+		ind.window = newRingBuffer(ind.Period)
+		ind.upper, ind.lower = math.NaN(), math.NaN()
+	}
+	ind.mean = ind.ma.update(value, volume)
+	ind.window.push(value)
+	if ind.window.full() {
+		stddev := ind.Factor * StdDev(ind.window.ordered())
+		ind.upper = ind.mean + stddev
+		ind.lower = ind.mean - stddev
+	}
+	return ind.mean
+}
+
+// Bands returns the upper, middle and lower band after the last Update.
+func (ind *BollingerBands) Bands() (upper, mean, lower float64) {
+	return ind.upper, ind.mean, ind.lower
+}
+
+// Reset clears the state of Update.
+func (ind *BollingerBands) Reset() {
+	ind.ma = nil
+	ind.window = ringBuffer{}
+	ind.upper, ind.mean, ind.lower = math.NaN(), math.NaN(), math.NaN()
+}
@@ -0,0 +1,57 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// CommodityChannelIndex is the deviation of the typical price (high+low+close)/3 from its
+// simple moving average over Period bars, divided by 0.015 times the mean absolute
+// deviation. Most values lie between -100 and 100. It is 0 if the typical price did not
+// change. The first Period-1 values are NaN.
+type CommodityChannelIndex struct {
+	BarHistoryIndicator
+}
+
+func CCI(bars BarHistory, period int) *CommodityChannelIndex {
+	return &CommodityChannelIndex{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+func (ind *CommodityChannelIndex) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	if len(ind.Input.High) != len(ind.Input.Close) || len(ind.Input.Low) != len(ind.Input.Close) {
+		return nil, fmt.Errorf("CCI requires high, low and close of every bar")
+	}
+
+	tp := make([]float64, len(ind.Input.Close))
+	for i := range tp {
+		tp[i] = typicalPrice(&ind.Input, i)
+	}
+	mean, err := MA(tp, ind.Period).Compute()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(tp))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		deviation := 0.0
+		for j := i - ind.Period + 1; j <= i; j++ {
+			deviation += math.Abs(tp[j] - mean[i])
+		}
+		deviation /= float64(ind.Period)
+		if deviation == 0 {
+			continue
+		}
+		res[i] = (tp[i] - mean[i]) / (0.015 * deviation)
+	}
+	return res, nil
+}
@@ -0,0 +1,154 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the channel indicators.
+const (
+	ChannelUpper  = "upper"
+	ChannelMiddle = "middle"
+	ChannelLower  = "lower"
+)
+
+// ChannelLines holds the bands of a price channel.
+type ChannelLines struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+func (lines ChannelLines) outputs() map[string][]float64 {
+	return map[string][]float64{ChannelUpper: lines.Upper, ChannelMiddle: lines.Middle, ChannelLower: lines.Lower}
+}
+
+// DonchianChannel is the highest high and the lowest low of the last Period bars, the
+// middle band is their average. The first Period-1 values are NaN. The window includes
+// the current bar, use Shift to compare the close to the channel of the previous bars.
+type DonchianChannel struct {
+	BarHistoryIndicator
+}
+
+func Donchian(bars BarHistory, period int) *DonchianChannel {
+	return &DonchianChannel{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+// Compute returns the upper band.
+func (ind *DonchianChannel) Compute() ([]float64, error) {
+	res, err := ind.ComputeChannel()
+	if err != nil {
+		return nil, err
+	}
+	return res.Upper, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *DonchianChannel) Outputs() []string {
+	return []string{ChannelUpper, ChannelMiddle, ChannelLower}
+}
+
+// ComputeAll returns the bands by output name.
+func (ind *DonchianChannel) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeChannel()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeChannel returns all bands.
+func (ind *DonchianChannel) ComputeChannel() (ChannelLines, error) {
+	err := CheckInput(ind.Input.High, ind.Period)
+	if err != nil {
+		return ChannelLines{}, err
+	}
+	if len(ind.Input.Low) != len(ind.Input.High) {
+		return ChannelLines{}, fmt.Errorf("Donchian channel requires high and low of every bar")
+	}
+
+	n := len(ind.Input.High)
+	res := ChannelLines{Upper: make([]float64, n), Middle: make([]float64, n), Lower: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		if i < ind.Period-1 {
+			res.Upper[i], res.Middle[i], res.Lower[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		res.Upper[i] = highest(ind.Input.High, i, ind.Period)
+		res.Lower[i] = lowest(ind.Input.Low, i, ind.Period)
+		res.Middle[i] = (res.Upper[i] + res.Lower[i]) / 2
+	}
+	return res, nil
+}
+
+// KeltnerChannel is a moving average of the close, by default an EMA over Period bars,
+// with bands Multiplier times the ATR over ATRPeriod bars above and below. The bands are
+// NaN for the first ATRPeriod-1 values, the warm-up of the middle band depends on Type.
+type KeltnerChannel struct {
+	BarHistoryIndicator
+	ATRPeriod  int
+	Multiplier float64
+	Type       maType
+}
+
+func Keltner(bars BarHistory, period, atrPeriod int, multiplier float64) *KeltnerChannel {
+	return &KeltnerChannel{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		ATRPeriod:           atrPeriod,
+		Multiplier:          multiplier,
+		Type:                EMA,
+	}
+}
+
+// WithType sets the type of the moving average of the middle band.
+func (ind *KeltnerChannel) WithType(matype maType) *KeltnerChannel {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns the middle band.
+func (ind *KeltnerChannel) Compute() ([]float64, error) {
+	res, err := ind.ComputeChannel()
+	if err != nil {
+		return nil, err
+	}
+	return res.Middle, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *KeltnerChannel) Outputs() []string {
+	return []string{ChannelUpper, ChannelMiddle, ChannelLower}
+}
+
+// ComputeAll returns the bands by output name.
+func (ind *KeltnerChannel) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeChannel()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeChannel returns all bands.
+func (ind *KeltnerChannel) ComputeChannel() (ChannelLines, error) {
+	if ind.Multiplier < 0 {
+		return ChannelLines{}, fmt.Errorf("invalid multiplier: %v", ind.Multiplier)
+	}
+	middle, err := MA(ind.Input.Close, ind.Period).WithType(ind.Type).WithVolume(volumeOf(&ind.Input)).Compute()
+	if err != nil {
+		return ChannelLines{}, err
+	}
+	atr, err := ATR(ind.Input, ind.ATRPeriod).Compute()
+	if err != nil {
+		return ChannelLines{}, err
+	}
+
+	res := ChannelLines{Upper: make([]float64, len(middle)), Middle: middle, Lower: make([]float64, len(middle))}
+	for i := range middle {
+		res.Upper[i] = middle[i] + ind.Multiplier*atr[i]
+		res.Lower[i] = middle[i] - ind.Multiplier*atr[i]
+	}
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDonchian(t *testing.T) {
+	got, err := Donchian(stochBars, 3).ComputeChannel()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got.Upper, []float64{nan, nan, 12, 13, 14}, 1e-9, "upper: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.Lower, []float64{nan, nan, 8, 9, 9}, 1e-9, "lower: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.Middle, []float64{nan, nan, 10, 11, 11.5}, 1e-9, "middle: "); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDonchianBreakout(t *testing.T) {
+	// the high of the previous 3 bars
+	high := Shift(Output(Donchian(BarHistory{}, 3), ChannelUpper), 1)
+	high.SetInput(&stochBars)
+	got, err := high.Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, 12, 13}, 1e-9); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Shift(Donchian(stochBars, 3), -1).Compute(); err == nil {
+		t.Errorf("Want error for negative shift")
+	}
+}
+
+func TestKeltner(t *testing.T) {
+	got, err := Keltner(TestBars, 10, TESTPERIOD, 2).ComputeChannel()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	ema, _ := MA(TestBars.Close, 10).WithType(EMA).Compute()
+	atr, _ := ATR(TestBars, TESTPERIOD).Compute()
+	for i := range ema {
+		if math.Abs(got.Middle[i]-ema[i]) > 1e-9 {
+			t.Fatalf("middle at index %d: %v != %v", i, got.Middle[i], ema[i])
+		}
+		if i < TESTPERIOD-1 {
+			if !math.IsNaN(got.Upper[i]) || !math.IsNaN(got.Lower[i]) {
+				t.Fatalf("Expected NaN during warm-up at index %d", i)
+			}
+			continue
+		}
+		if math.Abs(got.Upper[i]-ema[i]-2*atr[i]) > 1e-9 || math.Abs(ema[i]-got.Lower[i]-2*atr[i]) > 1e-9 {
+			t.Fatalf("bands at index %d: %v, %v", i, got.Upper[i], got.Lower[i])
+		}
+	}
+
+	if _, err := Keltner(TestBars, 10, TESTPERIOD, -1).Compute(); err == nil {
+		t.Errorf("Want error for negative multiplier")
+	}
+}
+
+func TestSupertrend(t *testing.T) {
+	got, err := Supertrend(TestBars, TESTPERIOD, 1).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	flips := 0
+	for i := TESTPERIOD - 1; i < TestBars.Len(); i++ {
+		close := TestBars.Close[i]
+		switch got.Direction[i] {
+		case 1:
+			if got.Supertrend[i] != got.Lower[i] || close < got.Supertrend[i] {
+				t.Errorf("up trend at index %d: close %v, line %v", i, close, got.Supertrend[i])
+			}
+		case -1:
+			if got.Supertrend[i] != got.Upper[i] || close > got.Supertrend[i] {
+				t.Errorf("down trend at index %d: close %v, line %v", i, close, got.Supertrend[i])
+			}
+		default:
+			t.Fatalf("invalid direction at index %d: %v", i, got.Direction[i])
+		}
+		if i > TESTPERIOD-1 && got.Direction[i] != got.Direction[i-1] {
+			flips++
+		}
+		// the lower band only falls if the close crossed it
+		if i > TESTPERIOD-1 && got.Lower[i] < got.Lower[i-1] && TestBars.Close[i-1] >= got.Lower[i-1] {
+			t.Errorf("lower band fell at index %d", i)
+		}
+	}
+	if flips == 0 {
+		t.Errorf("Expected the trend to turn at least once")
+	}
+	if !math.IsNaN(got.Supertrend[TESTPERIOD-2]) {
+		t.Errorf("Expected NaN during warm-up")
+	}
+}
+
+func TestSupertrendTurns(t *testing.T) {
+	// a steady rise followed by a crash
+	var bars BarHistory
+	for i := 0; i < 20; i++ {
+		price := 100 + float64(i)
+		if i >= 15 {
+			price = 80 - float64(i)
+		}
+		bars.Open = append(bars.Open, price)
+		bars.High = append(bars.High, price+1)
+		bars.Low = append(bars.Low, price-1)
+		bars.Close = append(bars.Close, price+0.5)
+	}
+	got, err := Supertrend(bars, 3, 2).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if got.Direction[14] != 1 || got.Direction[15] != -1 {
+		t.Errorf("Expected up trend until the crash, got %v", got.Direction)
+	}
+}
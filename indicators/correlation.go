@@ -0,0 +1,100 @@
+package indicators
+
+import "math"
+
+// RollingCorrelation is the Pearson correlation of the one bar returns of the close and the
+// close of the benchmark over the last Period bars. The benchmark is matched by timestamp,
+// see benchmarkClose, and values whose window lacks a benchmark bar are NaN. It is 0 if
+// either series did not change. The first Period values are NaN.
+type RollingCorrelation struct {
+	BarHistoryIndicator
+	Benchmark BarHistory
+}
+
+func Correlation(bars, benchmark BarHistory, period int) *RollingCorrelation {
+	return &RollingCorrelation{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		Benchmark:           benchmark,
+	}
+}
+
+func (ind *RollingCorrelation) Compute() ([]float64, error) {
+	return rollingCovariance(&ind.Input, &ind.Benchmark, ind.Period, func(cov, varInput, varBenchmark float64) float64 {
+		if varInput == 0 || varBenchmark == 0 {
+			return 0
+		}
+		return cov / math.Sqrt(varInput*varBenchmark)
+	})
+}
+
+// RollingBeta is the beta of the close against the close of the benchmark, the covariance of
+// their one bar returns over the last Period bars divided by the variance of the returns of
+// the benchmark. The benchmark is matched by timestamp like in RollingCorrelation. It is 0 if
+// the benchmark did not change. The first Period values are NaN.
+type RollingBeta struct {
+	BarHistoryIndicator
+	Benchmark BarHistory
+}
+
+func Beta(bars, benchmark BarHistory, period int) *RollingBeta {
+	return &RollingBeta{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		Benchmark:           benchmark,
+	}
+}
+
+func (ind *RollingBeta) Compute() ([]float64, error) {
+	return rollingCovariance(&ind.Input, &ind.Benchmark, ind.Period, func(cov, _, varBenchmark float64) float64 {
+		if varBenchmark == 0 {
+			return 0
+		}
+		return cov / varBenchmark
+	})
+}
+
+// rollingCovariance passes the covariance and the variances of the one bar returns over the
+// last period bars to stat. The first period values are NaN.
+func rollingCovariance(bars, benchmark *BarHistory, period int, stat func(cov, varInput, varBenchmark float64) float64) ([]float64, error) {
+	input := bars.Close
+	err := CheckInput(input, period)
+	if err != nil {
+		return nil, err
+	}
+	aligned, err := benchmarkClose(bars, benchmark)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := returns(input), returns(aligned)
+	res := make([]float64, len(input))
+	for i := range res {
+		if i < period {
+			res[i] = math.NaN()
+			continue
+		}
+		ra, rb := a[i-period+1:i+1], b[i-period+1:i+1]
+		meanA, meanB := Mean(ra), Mean(rb)
+		if math.IsNaN(meanA) || math.IsNaN(meanB) {
+			res[i] = math.NaN()
+			continue
+		}
+		cov, varA, varB := 0.0, 0.0, 0.0
+		for j := range ra {
+			cov += (ra[j] - meanA) * (rb[j] - meanB)
+			varA += (ra[j] - meanA) * (ra[j] - meanA)
+			varB += (rb[j] - meanB) * (rb[j] - meanB)
+		}
+		res[i] = stat(cov, varA, varB)
+	}
+	return res, nil
+}
+
+// returns returns the one bar returns of the values, the first one is NaN.
+func returns(values []float64) []float64 {
+	res := make([]float64, len(values))
+	res[0] = math.NaN()
+	for i := 1; i < len(values); i++ {
+		res[i] = values[i]/values[i-1] - 1
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CrossSectionalRank computes the indicator for every history and ranks the values of each
+// timestamp across all histories that have a value there. The rank is the percentage of the
+// other histories with a lower value, equal values count half, and 50 for a history without
+// peers. The ranks are aligned with the bars of each history. Histories whose indicator fails
+// and values within the warm-up period are NaN. The indicator is shared between the
+// histories, hence it is computed sequentially. All histories require timestamps.
+//
+// If the indicator fails for some histories, the ranks of the others are returned together
+// with a RankError holding the failures by symbol.
+func CrossSectionalRank(ind Indicator, histories map[string]*BarHistory) (map[string][]float64, error) {
+	symbols := make([]string, 0, len(histories))
+	for symbol, bars := range histories {
+		if !bars.HasTime() {
+			return nil, fmt.Errorf("bar history of %s has no timestamps", symbol)
+		}
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	values := make(map[string][]float64, len(histories))
+	byTime := make(map[int64][]float64)
+	failed := make(RankError)
+	for _, symbol := range symbols {
+		bars := histories[symbol]
+		ind.SetInput(bars)
+		v, err := ind.Compute()
+		if err == nil && len(v) != bars.Len() {
+			err = fmt.Errorf("%d values for %d bars", len(v), bars.Len())
+		}
+		if err != nil {
+			failed[symbol] = err
+			v = nil
+		}
+		values[symbol] = v
+		for i, value := range v {
+			if !math.IsNaN(value) {
+				key := bars.Time[i].UnixNano()
+				byTime[key] = append(byTime[key], value)
+			}
+		}
+	}
+	for _, v := range byTime {
+		sort.Float64s(v)
+	}
+
+	res := make(map[string][]float64, len(histories))
+	for _, symbol := range symbols {
+		bars := histories[symbol]
+		ranks := make([]float64, bars.Len())
+		for i := range ranks {
+			ranks[i] = math.NaN()
+			if values[symbol] == nil || math.IsNaN(values[symbol][i]) {
+				continue
+			}
+			value := values[symbol][i]
+			peers := byTime[bars.Time[i].UnixNano()]
+			if len(peers) == 1 {
+				ranks[i] = 50
+				continue
+			}
+			below := sort.SearchFloat64s(peers, value)
+			equal := sort.Search(len(peers), func(j int) bool { return peers[j] > value }) - below
+			ranks[i] = 100 * (float64(below) + 0.5*float64(equal-1)) / float64(len(peers)-1)
+		}
+		res[symbol] = ranks
+	}
+	if len(failed) > 0 {
+		return res, failed
+	}
+	return res, nil
+}
+
+// RankError maps the symbols of the histories whose indicator failed in CrossSectionalRank
+// to the error.
+type RankError map[string]error
+
+func (e RankError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for symbol, err := range e {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", symbol, err))
+	}
+	sort.Strings(msgs)
+	return "indicator failed for " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,261 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// drawdown returns the percentage the value at index i is below the highest of the window of
+// period values ending at i.
+func drawdown(values []float64, i, period int) float64 {
+	peak := highest(values, i, period)
+	return (peak - values[i]) / peak * 100
+}
+
+// CurrentDrawdown is the percentage the input is below its highest value of the last Period
+// values, 0 at a new high. The first Period-1 values are NaN.
+type CurrentDrawdown struct {
+	TimeSeriesIndicator
+}
+
+func Drawdown(input []float64, period int) *CurrentDrawdown {
+	return &CurrentDrawdown{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *CurrentDrawdown) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= ind.Period-1 {
+			res[i] = drawdown(ind.Input, i, ind.Period)
+		}
+	}
+	return res, nil
+}
+
+// MaximumDrawdown is the largest decline in percent from a peak to a later trough within the
+// last Period values, e.g. to filter out assets with an excessive recent drawdown. The first
+// Period-1 values are NaN.
+type MaximumDrawdown struct {
+	TimeSeriesIndicator
+}
+
+func MaxDrawdown(input []float64, period int) *MaximumDrawdown {
+	return &MaximumDrawdown{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *MaximumDrawdown) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		peak := ind.Input[i-ind.Period+1]
+		for _, value := range ind.Input[i-ind.Period+1 : i+1] {
+			peak = math.Max(peak, value)
+			res[i] = math.Max(res[i], (peak-value)/peak*100)
+		}
+	}
+	return res, nil
+}
+
+// UlcerIndex is the root mean square of the drawdowns of the last Period values, each
+// measured from the highest of the Period values before it, see CurrentDrawdown. It weighs
+// deep and long drawdowns more than short dips. The first 2*Period-2 values are NaN.
+type UlcerIndex struct {
+	TimeSeriesIndicator
+}
+
+func Ulcer(input []float64, period int) *UlcerIndex {
+	return &UlcerIndex{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *UlcerIndex) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, 2*ind.Period-1)
+	if err != nil {
+		return nil, err
+	}
+	dd, err := Drawdown(ind.Input, ind.Period).Compute()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		res[i] = math.NaN()
+		if i < 2*ind.Period-2 {
+			continue
+		}
+		sum := 0.0
+		for _, d := range dd[i-ind.Period+1 : i+1] {
+			sum += d * d
+		}
+		res[i] = math.Sqrt(sum / float64(ind.Period))
+	}
+	return res, nil
+}
+
+// riskRatio holds the parameters shared by the risk measures over the one bar returns.
+type riskRatio struct {
+	// BarsPerYear annualizes the measure, defaults to TradingDays.
+	BarsPerYear float64
+}
+
+// windows passes the one bar returns of the last period bars to stat. The first period values
+// are NaN.
+func (r riskRatio) windows(input []float64, period int, stat func(returns []float64) float64) ([]float64, error) {
+	err := CheckInput(input, period)
+	if err != nil {
+		return nil, err
+	}
+	if r.BarsPerYear <= 0 {
+		return nil, fmt.Errorf("invalid bars per year: %v", r.BarsPerYear)
+	}
+	ret := returns(input)
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= period {
+			res[i] = stat(ret[i-period+1 : i+1])
+		}
+	}
+	return res, nil
+}
+
+// downsideDeviation returns the root mean square of the returns below the target.
+func downsideDeviation(returns []float64, target float64) float64 {
+	sum := 0.0
+	for _, r := range returns {
+		if r < target {
+			sum += (r - target) * (r - target)
+		}
+	}
+	return math.Sqrt(sum / float64(len(returns)))
+}
+
+// DownsideDeviation is the annualized root mean square in percent of the one bar returns
+// below Target over the last Period bars. Target is a return per bar and defaults to 0. The
+// first Period values are NaN.
+type DownsideDeviation struct {
+	TimeSeriesIndicator
+	riskRatio
+	Target float64
+}
+
+func DownsideDev(input []float64, period int) *DownsideDeviation {
+	return &DownsideDeviation{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+		riskRatio:           riskRatio{BarsPerYear: TradingDays},
+	}
+}
+
+// WithTarget sets the minimum acceptable return per bar.
+func (ind *DownsideDeviation) WithTarget(target float64) *DownsideDeviation {
+	ind.Target = target
+	return ind
+}
+
+// WithBarsPerYear sets the number of bars per year, e.g. 52 for weekly bars.
+func (ind *DownsideDeviation) WithBarsPerYear(bars float64) *DownsideDeviation {
+	ind.BarsPerYear = bars
+	return ind
+}
+
+func (ind *DownsideDeviation) Compute() ([]float64, error) {
+	return ind.windows(ind.Input, ind.Period, func(returns []float64) float64 {
+		return downsideDeviation(returns, ind.Target) * math.Sqrt(ind.BarsPerYear) * 100
+	})
+}
+
+// SharpeRatio is the annualized mean excess return over the last Period bars divided by the
+// standard deviation of the returns, using the population standard deviation like StdDev.
+// RiskFree is an annual rate and defaults to 0. The ratio is NaN if the returns did not vary
+// and for the first Period values.
+type SharpeRatio struct {
+	TimeSeriesIndicator
+	riskRatio
+	RiskFree float64
+}
+
+func Sharpe(input []float64, period int) *SharpeRatio {
+	return &SharpeRatio{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+		riskRatio:           riskRatio{BarsPerYear: TradingDays},
+	}
+}
+
+// WithRiskFree sets the annual risk-free rate, e.g. 0.04 for 4%.
+func (ind *SharpeRatio) WithRiskFree(rate float64) *SharpeRatio {
+	ind.RiskFree = rate
+	return ind
+}
+
+// WithBarsPerYear sets the number of bars per year, e.g. 52 for weekly bars.
+func (ind *SharpeRatio) WithBarsPerYear(bars float64) *SharpeRatio {
+	ind.BarsPerYear = bars
+	return ind
+}
+
+func (ind *SharpeRatio) Compute() ([]float64, error) {
+	return ind.windows(ind.Input, ind.Period, func(returns []float64) float64 {
+		sd := StdDev(returns)
+		if sd == 0 {
+			return math.NaN()
+		}
+		return (Mean(returns) - ind.RiskFree/ind.BarsPerYear) / sd * math.Sqrt(ind.BarsPerYear)
+	})
+}
+
+// SortinoRatio is the annualized mean excess return over the last Period bars divided by the
+// downside deviation of the returns below the risk-free return. RiskFree is an annual rate
+// and defaults to 0. The ratio is NaN if no return fell below the risk-free return and for
+// the first Period values.
+type SortinoRatio struct {
+	TimeSeriesIndicator
+	riskRatio
+	RiskFree float64
+}
+
+func Sortino(input []float64, period int) *SortinoRatio {
+	return &SortinoRatio{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+		riskRatio:           riskRatio{BarsPerYear: TradingDays},
+	}
+}
+
+// WithRiskFree sets the annual risk-free rate, e.g. 0.04 for 4%.
+func (ind *SortinoRatio) WithRiskFree(rate float64) *SortinoRatio {
+	ind.RiskFree = rate
+	return ind
+}
+
+// WithBarsPerYear sets the number of bars per year, e.g. 52 for weekly bars.
+func (ind *SortinoRatio) WithBarsPerYear(bars float64) *SortinoRatio {
+	ind.BarsPerYear = bars
+	return ind
+}
+
+func (ind *SortinoRatio) Compute() ([]float64, error) {
+	return ind.windows(ind.Input, ind.Period, func(returns []float64) float64 {
+		target := ind.RiskFree / ind.BarsPerYear
+		dd := downsideDeviation(returns, target)
+		if dd == 0 {
+			return math.NaN()
+		}
+		return (Mean(returns) - target) / dd * math.Sqrt(ind.BarsPerYear)
+	})
+}
@@ -0,0 +1,94 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+var drawdownInput = []float64{100, 110, 99, 104.5, 88, 121}
+
+func TestDrawdown(t *testing.T) {
+	got, err := Drawdown(drawdownInput, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 10, 5, 16.5 / 104.5 * 100, 0}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	got, err = MaxDrawdown(drawdownInput, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 10, 10, 16.5 / 104.5 * 100, 16.5 / 104.5 * 100}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUlcer(t *testing.T) {
+	got, err := Ulcer(drawdownInput, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	dd := 16.5 / 104.5 * 100
+	want := []float64{nan, nan, math.Sqrt(50), math.Sqrt(50), dd / math.Sqrt(2), dd / math.Sqrt(2)}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := Ulcer(drawdownInput, 3).Compute(); err != nil {
+		t.Errorf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := Ulcer(drawdownInput, 4).Compute(); err == nil {
+		t.Errorf("Want error for too few values")
+	}
+}
+
+func TestRiskRatios(t *testing.T) {
+	got, err := DownsideDev(drawdownInput, 2).WithBarsPerYear(1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if math.Abs(got[2]-math.Sqrt(0.005)*100) > 1e-9 || !math.IsNaN(got[1]) {
+		t.Errorf("Unexpected downside deviation: %v", got)
+	}
+
+	got, err = Sharpe(drawdownInput, 2).WithBarsPerYear(1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if math.Abs(got[2]) > 1e-9 || math.Abs(got[3]+2./7) > 1e-9 {
+		t.Errorf("Unexpected Sharpe ratio: %v", got)
+	}
+	got, err = Sharpe(drawdownInput, 2).WithBarsPerYear(1).WithRiskFree(0.1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if math.Abs(got[2]+1) > 1e-9 {
+		t.Errorf("Expected Sharpe ratio -1, got %v", got[2])
+	}
+
+	got, err = Sortino(drawdownInput, 2).WithBarsPerYear(1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if math.Abs(got[3]+math.Sqrt2/4.5) > 1e-9 {
+		t.Errorf("Unexpected Sortino ratio: %v", got)
+	}
+
+	// without variation or losses the ratios are undefined
+	growth := []float64{100, 101, 102.01, 103.0301}
+	for name, ind := range map[string]Indicator{"Sharpe": Sharpe(growth, 2), "Sortino": Sortino(growth, 2)} {
+		got, err := ind.Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if !math.IsNaN(got[3]) {
+			t.Errorf("Expected NaN %s ratio, got %v", name, got[3])
+		}
+	}
+
+	if _, err := Sharpe(drawdownInput, 2).WithBarsPerYear(0).Compute(); err == nil {
+		t.Errorf("Want error for invalid bars per year")
+	}
+}
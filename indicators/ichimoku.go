@@ -0,0 +1,135 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the Ichimoku cloud.
+const (
+	IchimokuTenkan  = "tenkan"
+	IchimokuKijun   = "kijun"
+	IchimokuSenkouA = "senkouA"
+	IchimokuSenkouB = "senkouB"
+	IchimokuChikou  = "chikou"
+)
+
+// IchimokuLines holds the outputs of the Ichimoku cloud, each aligned with the bars.
+type IchimokuLines struct {
+	Tenkan  []float64
+	Kijun   []float64
+	SenkouA []float64
+	SenkouB []float64
+	Chikou  []float64
+}
+
+// IchimokuCloud is the Ichimoku Kinko Hyo system. The conversion line (tenkan) and the base
+// line (kijun) are the midpoints of the range of the last Conversion and Base bars. The
+// leading spans form the cloud and are plotted Displacement bars ahead: senkou A is the
+// average of tenkan and kijun and senkou B the midpoint of the last SpanB bars, both as of
+// Displacement bars ago. Hence the value at bar i is the cloud drawn at bar i and compares
+// directly with the close of bar i; the cloud of future bars is not part of the output.
+// The lagging span (chikou) is the close plotted Displacement bars back, i.e. its value at
+// bar i is the close of bar i+Displacement and the last Displacement values are NaN. Since
+// it looks ahead it must not be used for signals at bar i. Each line is NaN until its
+// window is full.
+type IchimokuCloud struct {
+	BarHistoryIndicator
+	Conversion   int
+	Base         int
+	SpanB        int
+	Displacement int
+}
+
+// Ichimoku creates the Ichimoku cloud displaced by the base period, the common periods are
+// 9, 26 and 52.
+func Ichimoku(bars BarHistory, conversion, base, spanB int) *IchimokuCloud {
+	return &IchimokuCloud{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, spanB),
+		Conversion:          conversion,
+		Base:                base,
+		SpanB:               spanB,
+		Displacement:        base,
+	}
+}
+
+// WithDisplacement sets the number of bars the spans are shifted.
+func (ind *IchimokuCloud) WithDisplacement(displacement int) *IchimokuCloud {
+	ind.Displacement = displacement
+	return ind
+}
+
+// Compute returns the conversion line.
+func (ind *IchimokuCloud) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.Tenkan, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *IchimokuCloud) Outputs() []string {
+	return []string{IchimokuTenkan, IchimokuKijun, IchimokuSenkouA, IchimokuSenkouB, IchimokuChikou}
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *IchimokuCloud) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{
+		IchimokuTenkan:  res.Tenkan,
+		IchimokuKijun:   res.Kijun,
+		IchimokuSenkouA: res.SenkouA,
+		IchimokuSenkouB: res.SenkouB,
+		IchimokuChikou:  res.Chikou,
+	}, nil
+}
+
+// ComputeLines returns all lines.
+func (ind *IchimokuCloud) ComputeLines() (IchimokuLines, error) {
+	if ind.Conversion <= 0 || ind.Base <= 0 || ind.SpanB <= 0 || ind.Displacement < 0 {
+		return IchimokuLines{}, fmt.Errorf("invalid periods: conversion %d, base %d, span B %d, displacement %d",
+			ind.Conversion, ind.Base, ind.SpanB, ind.Displacement)
+	}
+	bars := ind.Input
+	err := CheckInput(bars.Close, ind.SpanB)
+	if err != nil {
+		return IchimokuLines{}, err
+	}
+	if len(bars.High) != len(bars.Close) || len(bars.Low) != len(bars.Close) {
+		return IchimokuLines{}, fmt.Errorf("Ichimoku requires high, low and close of every bar")
+	}
+
+	n := len(bars.Close)
+	midpoint := func(i, period int) float64 {
+		if i < period-1 {
+			return math.NaN()
+		}
+		return (highest(bars.High, i, period) + lowest(bars.Low, i, period)) / 2
+	}
+	res := IchimokuLines{
+		Tenkan:  make([]float64, n),
+		Kijun:   make([]float64, n),
+		SenkouA: make([]float64, n),
+		SenkouB: make([]float64, n),
+		Chikou:  make([]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		res.Tenkan[i] = midpoint(i, ind.Conversion)
+		res.Kijun[i] = midpoint(i, ind.Base)
+	}
+	for i := 0; i < n; i++ {
+		res.SenkouA[i], res.SenkouB[i], res.Chikou[i] = math.NaN(), math.NaN(), math.NaN()
+		if j := i - ind.Displacement; j >= 0 {
+			res.SenkouA[i] = (res.Tenkan[j] + res.Kijun[j]) / 2
+			res.SenkouB[i] = midpoint(j, ind.SpanB)
+		}
+		if j := i + ind.Displacement; j < n {
+			res.Chikou[i] = bars.Close[j]
+		}
+	}
+	return res, nil
+}
@@ -1,6 +1,16 @@
 package indicators
 
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// BarHistory holds bars as parallel columns in chronological order, i.e. the last
+// element is the most recent bar. Time is optional and, if set, holds the start of
+// every bar.
 type BarHistory struct {
+	Time   []time.Time
 	Open   []float64
 	High   []float64
 	Low    []float64
@@ -8,11 +18,29 @@ type BarHistory struct {
 	Volume []int64
 }
 
+// Indicator is implemented by all indicators. Compute returns one value per input bar,
+// aligned with the input, so that the last value belongs to the most recent bar. Values
+// within the warm-up period, i.e. before the indicator has seen enough bars to be defined,
+// are NaN. An indicator that is defined from the first bar on, e.g. the EMA or the true
+// range, has no warm-up period. Compute returns an error if the parameters are invalid or
+// the input is too short.
 type Indicator interface {
-	Compute() []float64
+	Compute() ([]float64, error)
 	SetInput(bars *BarHistory)
 }
 
+// Latest computes the indicator and returns its most recent value.
+func Latest(ind Indicator) (float64, error) {
+	values, err := ind.Compute()
+	if err != nil {
+		return math.NaN(), err
+	}
+	if len(values) == 0 {
+		return math.NaN(), errors.New("indicator returned no values")
+	}
+	return values[len(values)-1], nil
+}
+
 type GeneralIndicator struct {
 	Values []float64
 	Period int
@@ -20,11 +48,26 @@ type GeneralIndicator struct {
 
 type TimeSeriesIndicator struct {
 	Input []float64
+	// Volume belongs to Input and is only used by volume weighted averages.
+	Volume []float64
 	GeneralIndicator
 }
 
 func (ind *TimeSeriesIndicator) SetInput(bars *BarHistory) {
 	ind.Input = bars.Close
+	ind.Volume = volumeOf(bars)
+}
+
+// volumeOf returns the volume of the bars as floats, or nil if it is missing.
+func volumeOf(bars *BarHistory) []float64 {
+	if len(bars.Volume) != len(bars.Close) {
+		return nil
+	}
+	res := make([]float64, len(bars.Volume))
+	for i, v := range bars.Volume {
+		res[i] = float64(v)
+	}
+	return res
 }
 
 type BarHistoryIndicator struct {
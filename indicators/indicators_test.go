@@ -1,12 +1,59 @@
 package indicators
 
 import (
+	"math"
 	"testing"
+	"time"
 	// "fmt"
 )
 
 var ACC = 1e-4
 var TESTPERIOD = 5
+var nan = math.NaN()
+
+// day returns the given day of June 2023.
+func day(d int) time.Time {
+	return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dated returns bars with the closes on consecutive days starting at the given day.
+func dated(first int, closes ...float64) *BarHistory {
+	bars := &BarHistory{Close: closes}
+	for i := range closes {
+		bars.Time = append(bars.Time, day(first+i))
+	}
+	return bars
+}
+
+// datedBars returns bars with flat prices at the closes on the given days.
+func datedBars(days []int, closes []float64) *BarHistory {
+	bars := &BarHistory{}
+	for i, d := range days {
+		bars.Time = append(bars.Time, day(d))
+		bars.Open = append(bars.Open, closes[i])
+		bars.High = append(bars.High, closes[i])
+		bars.Low = append(bars.Low, closes[i])
+		bars.Close = append(bars.Close, closes[i])
+		bars.Volume = append(bars.Volume, int64(i))
+	}
+	return bars
+}
+
+// days returns bars with increasing prices on the given dates.
+func days(dates ...string) BarHistory {
+	var bars BarHistory
+	for i, date := range dates {
+		d, _ := time.Parse("2006-01-02", date)
+		p := float64(10 + i)
+		bars.Time = append(bars.Time, d)
+		bars.Open = append(bars.Open, p)
+		bars.High = append(bars.High, p+1)
+		bars.Low = append(bars.Low, p-1)
+		bars.Close = append(bars.Close, p+0.5)
+		bars.Volume = append(bars.Volume, int64(100*(i+1)))
+	}
+	return bars
+}
 
 var TestBars = BarHistory{
 	Open: []float64{
@@ -61,9 +108,9 @@ var TestBars = BarHistory{
 
 // Moving average
 func TestSma(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,129.1708190917970,129.53110046386700,130.39023132324200,131.09297790527300,131.41366882324200,131.9382537841800,132.42918701171900,132.8943878173830,133.53972778320300,134.30384216308600,135.65984802246100,137.09305725097700,138.72422485351600,139.96739501953100,141.51937561035200,142.41809692382800,143.13469848632800,144.0413360595700,145.07862854003900,145.33201293945300,144.92620239257800,145.02716064453100,144.28482360839800,143.95225830078100,144.38182373046900,145.67646179199200,145.7991973876950,145.71605529785200}
+	want := []float64{nan,nan,nan,nan,129.1708190917970,129.53110046386700,130.39023132324200,131.09297790527300,131.41366882324200,131.9382537841800,132.42918701171900,132.8943878173830,133.53972778320300,134.30384216308600,135.65984802246100,137.09305725097700,138.72422485351600,139.96739501953100,141.51937561035200,142.41809692382800,143.13469848632800,144.0413360595700,145.07862854003900,145.33201293945300,144.92620239257800,145.02716064453100,144.28482360839800,143.95225830078100,144.38182373046900,145.67646179199200,145.7991973876950,145.71605529785200}
 
-	got, err := MA(TestBars.Close, TESTPERIOD).Compute(SMA)
+	got, err := MA(TestBars.Close, TESTPERIOD).WithType(SMA).Compute()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
@@ -77,7 +124,7 @@ func TestSma(t *testing.T) {
 func TestEma(t *testing.T) {
 	want := []float64{129.14706420898400,128.86992390950500,128.85342746310800,129.3835115785950,129.2980989055870,129.84822296017000,130.76924784258700,131.2908877218810,131.54296994772500,131.61204608657000,132.2090764600570,133.11848485195200,133.93261233782500,134.5776436581590,135.8951716591500,137.45317555597000,139.33316358679300,140.14768177107800,141.3076575983620,141.87971647508000,142.63720803742300,144.30024786316500,145.18789208944100,145.09010514296000,143.72499726002000,144.03565898063900,143.99532009842100,144.43033140024900,145.3010070663120,146.02332864479400,145.77243922999300,145.0146034482500}
 
-	got, err := MA(TestBars.Close, TESTPERIOD).Compute(EMA)
+	got, err := MA(TestBars.Close, TESTPERIOD).WithType(EMA).Compute()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
@@ -115,9 +162,9 @@ func TestComputeLwmaWeights5(t *testing.T) {
 
 
 func TestLwma(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,129.31004943847700,129.90260009765600,130.92933247884100,131.57731119791700,131.8953633626300,132.0075398763020,132.49583435058600,133.33187255859400,134.22069905599000,134.99669189453100,136.40548706054700,138.04193216959600,140.04195963541700,141.05945739746100,142.27952880859400,142.7810150146480,143.35904642740900,144.85625610351600,145.8302042643230,145.76883850097700,144.3230946858720,144.233354695638,143.86251525878900,144.201025390625,145.2310587565100,146.25977478027300,146.12450764974000,145.35775248209600}
+	want := []float64{nan,nan,nan,nan,129.31004943847700,129.90260009765600,130.92933247884100,131.57731119791700,131.8953633626300,132.0075398763020,132.49583435058600,133.33187255859400,134.22069905599000,134.99669189453100,136.40548706054700,138.04193216959600,140.04195963541700,141.05945739746100,142.27952880859400,142.7810150146480,143.35904642740900,144.85625610351600,145.8302042643230,145.76883850097700,144.3230946858720,144.233354695638,143.86251525878900,144.201025390625,145.2310587565100,146.25977478027300,146.12450764974000,145.35775248209600}
 
-	got, err := MA(TestBars.Close, TESTPERIOD).Compute(LWMA)
+	got, err := MA(TestBars.Close, TESTPERIOD).WithType(LWMA).Compute()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
@@ -129,9 +176,9 @@ func TestLwma(t *testing.T) {
 }
 
 func TestWilderMA(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,129.1708190917970,129.52634948730500,130.14333911132800,130.58150478515600,130.87463070800800,131.04974423925800,131.5204228328130,132.20379859339800,132.8752123366330,133.4737111290720,134.48501443548400,135.70184821830900,137.1801065043350,138.09942883139800,139.20506491570400,139.9688187782660,140.80549325503500,142.16966010695800,143.12836419396500,143.48159760517200,142.98423438296500,143.31878399074700,143.43795565939500,143.81043532829700,144.45681994232500,145.05905031421200,145.10137233144700,144.78088424211100}
+	want := []float64{nan,nan,nan,nan,129.1708190917970,129.52634948730500,130.14333911132800,130.58150478515600,130.87463070800800,131.04974423925800,131.5204228328130,132.20379859339800,132.8752123366330,133.4737111290720,134.48501443548400,135.70184821830900,137.1801065043350,138.09942883139800,139.20506491570400,139.9688187782660,140.80549325503500,142.16966010695800,143.12836419396500,143.48159760517200,142.98423438296500,143.31878399074700,143.43795565939500,143.81043532829700,144.45681994232500,145.05905031421200,145.10137233144700,144.78088424211100}
 
-	got, err := MA(TestBars.Close, TESTPERIOD).Compute(WILDER)
+	got, err := MA(TestBars.Close, TESTPERIOD).WithType(WILDER).Compute()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
@@ -144,10 +191,13 @@ func TestWilderMA(t *testing.T) {
 
 //RSI
 func TestRSI(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,0.0,67.78115545017400,75.57844200494920,71.95086723077780,67.74136625223780,62.97687377547850,75.14230376246180,82.00313082238280,84.216434549137,85.32642287480910,91.67594306166710,94.11402390768810,95.9496658449414,79.73705928329040,84.37664820463020,77.1715707202275,80.96805008125100,88.39552173112490,80.86553635442170,60.70244408751380,38.236271644787700,56.94252794984710,52.8841745284969,59.602321535495700,66.99704462357330,68.74435492312840,51.23800087313730,40.772853432675}
+	want := []float64{nan,nan,nan,nan,nan,67.78115545017400,75.57844200494920,71.95086723077780,67.74136625223780,62.97687377547850,75.14230376246180,82.00313082238280,84.216434549137,85.32642287480910,91.67594306166710,94.11402390768810,95.9496658449414,79.73705928329040,84.37664820463020,77.1715707202275,80.96805008125100,88.39552173112490,80.86553635442170,60.70244408751380,38.236271644787700,56.94252794984710,52.8841745284969,59.602321535495700,66.99704462357330,68.74435492312840,51.23800087313730,40.772853432675}
 
-    got := RSI(TestBars.Close, TESTPERIOD).Compute()
-	_, err := sliceAlmostEqual(got, want,1)
+	got, err := RSI(TestBars.Close, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	_, err = sliceAlmostEqual(got, want,1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -155,10 +205,13 @@ func TestRSI(t *testing.T) {
 
 // ROC
 func TestROC(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,0.0,1.394849252969890,3.3477245533334900,2.727620755104950,1.229231337374540,2.0312709564625600,1.874528291663370,1.7540014088438800,2.4382968439181000,2.893339500922180,5.146124545581220,5.371722354217950,6.0441685981772900,4.5852840524274800,5.7113667150856300,3.2437733217149500,2.548928383249330,3.167998044733950,3.658190477533240,0.8820880634720110,-1.4186815402620900,0.3501793872823950,-2.5142433894765600,-1.1314579148011900,1.4823383118108600,4.591085030963500,0.4242297663350310,-0.2888590364933210}
+	want := []float64{nan,nan,nan,nan,nan,1.394849252969890,3.3477245533334900,2.727620755104950,1.229231337374540,2.0312709564625600,1.874528291663370,1.7540014088438800,2.4382968439181000,2.893339500922180,5.146124545581220,5.371722354217950,6.0441685981772900,4.5852840524274800,5.7113667150856300,3.2437733217149500,2.548928383249330,3.167998044733950,3.658190477533240,0.8820880634720110,-1.4186815402620900,0.3501793872823950,-2.5142433894765600,-1.1314579148011900,1.4823383118108600,4.591085030963500,0.4242297663350310,-0.2888590364933210}
 
-    got := ROC(TestBars.Close, TESTPERIOD).Compute()
-	_, err := sliceAlmostEqual(got, want,ACC)
+	got, err := ROC(TestBars.Close, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	_, err = sliceAlmostEqual(got, want,ACC)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -177,43 +230,55 @@ func TestTR(t *testing.T) {
 		1.898101806640630, 2.108245849609380, 3.62261962890625, 4.3847503662109400,
 	}
 
-	got := TR(TestBars).Compute()
+	got, err := TR(TestBars).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
 
-	_, err := sliceAlmostEqual(got, want,ACC)
+	_, err = sliceAlmostEqual(got, want,ACC)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
 func TestATR(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,2.2881851196289100,2.4640106201171900,2.45818359375,2.182321411132810,2.0843672607421900,1.8812877539062500,1.9276681425781200,1.965761223046880,1.8774643251171900,1.8127655274765600,1.9906329542078100,2.2239852452021900,2.3473217410836200,2.650882905562210,2.7141389416372700,2.6305732382707500,2.86263424979629,3.068079811946410,3.0305197577602500,3.1924883159738300,3.488347342232190,3.7111185476138700,3.2697890031301600,3.1691240491838200,2.9149196006751800,2.7535848504620200,2.9273918061508600,3.218863518162880}
+	want := []float64{nan,nan,nan,nan,2.2881851196289100,2.4640106201171900,2.45818359375,2.182321411132810,2.0843672607421900,1.8812877539062500,1.9276681425781200,1.965761223046880,1.8774643251171900,1.8127655274765600,1.9906329542078100,2.2239852452021900,2.3473217410836200,2.650882905562210,2.7141389416372700,2.6305732382707500,2.86263424979629,3.068079811946410,3.0305197577602500,3.1924883159738300,3.488347342232190,3.7111185476138700,3.2697890031301600,3.1691240491838200,2.9149196006751800,2.7535848504620200,2.9273918061508600,3.218863518162880}
 
-	got := ATR(TestBars, TESTPERIOD).Compute()
+	got, err := ATR(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
 
-	_, err := sliceAlmostEqual(got, want,ACC)
+	_, err = sliceAlmostEqual(got, want,ACC)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
 func TestATRP(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,1.7720385915012000,1.8816642913016700,1.8536758467043500,1.6490989837941100,1.5785024568859100,1.427920243964220,1.4449946102740700,1.4567960076401800,1.3849603962991500,1.33421368245487,1.4369664930293900,1.5821285947652700,1.6404152895454700,1.8697589705462900,1.889705576633000,1.8392551510455000,1.9858416488286700,2.0782741558357700,2.0620945644915,2.2033187094311600,2.4740967752605500,2.5654610551676200,2.2720335819213800,2.181084878223090,1.9823672800300700,1.8672426404315600,2.015129412974700,2.2431271619134600}
+	want := []float64{nan,nan,nan,nan,1.7720385915012000,1.8816642913016700,1.8536758467043500,1.6490989837941100,1.5785024568859100,1.427920243964220,1.4449946102740700,1.4567960076401800,1.3849603962991500,1.33421368245487,1.4369664930293900,1.5821285947652700,1.6404152895454700,1.8697589705462900,1.889705576633000,1.8392551510455000,1.9858416488286700,2.0782741558357700,2.0620945644915,2.2033187094311600,2.4740967752605500,2.5654610551676200,2.2720335819213800,2.181084878223090,1.9823672800300700,1.8672426404315600,2.015129412974700,2.2431271619134600}
 
-	got := ATRP(TestBars, TESTPERIOD).Compute()
+	got, err := ATRP(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
 
-	_, err := sliceAlmostEqual(got, want,ACC)
+	_, err = sliceAlmostEqual(got, want,ACC)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
 func TestADX(t *testing.T) {
-	want := []float64{0.0,0.0,0.0,0.0,0.0,0.0,0.0,0.0,0.0,32.6,38.08,45.064,51.85120000000000,56.28096000000000,62.224768,67.9798144,73.18385152000000,67.547081216,65.4376649728,64.35013197824,64.880105582592,67.1040844660736,65.48326757285890,58.7866140582871,52.22929124662970,43.583432997303700,36.866746397843000,35.6933971182744,35.55471769461950,37.64377415569560,31.315019324556500,30.652015459645200}
+	want := []float64{nan,nan,nan,nan,nan,nan,nan,nan,nan,32.6,38.08,45.064,51.85120000000000,56.28096000000000,62.224768,67.9798144,73.18385152000000,67.547081216,65.4376649728,64.35013197824,64.880105582592,67.1040844660736,65.48326757285890,58.7866140582871,52.22929124662970,43.583432997303700,36.866746397843000,35.6933971182744,35.55471769461950,37.64377415569560,31.315019324556500,30.652015459645200}
 
 	adx := ADX(TestBars, TESTPERIOD)
-	got := adx.Compute()
+	got, err := adx.Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
 
-	_, err := sliceAlmostEqual(got, want,1)
+	_, err = sliceAlmostEqual(got, want,1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -294,7 +359,7 @@ func TestBBBadFactor(t *testing.T) {
 	input := []float64{90.7043,92.9001,92.9784,91.8021,92.6647,92.6843,92.3021,92.7725,92.5373,92.9490,93.2039,91.0669,89.8318,89.7435,90.3994,90.7387,88.0177,88.0867,88.8439,90.7781,90.5416,91.3894,90.6500}
 	period := 5
 
-	_, err := BB(input, period).Compute(-1.0, SMA)
+	_, err := BB(input, period).WithFactor(-1.0).WithType(SMA).ComputeBands()
 	if err == nil {
 		t.Errorf("Want error for bad factor: %v ", err)
 	}
@@ -304,29 +369,29 @@ func TestBBBadFactor(t *testing.T) {
 func TestBBPeriod5F0(t *testing.T) {
 	input := []float64{90.7043,92.9001,92.9784,91.8021,92.6647,92.6843,92.3021,92.7725,92.5373,92.9490,93.2039,91.0669,89.8318,89.7435,90.3994,90.7387,88.0177,88.0867,88.8439,90.7781,90.5416,91.3894,90.6500}
 	period := 5
-	want := BBands{mean:  []float64{0,0,0,0,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
-	               upper:  []float64{0,0,0,0,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
-	               lower:  []float64{0,0,0,0,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
-			       band_width: []float64{0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0},
+	want := BBands{Mean:  []float64{nan,nan,nan,nan,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
+	               Upper:  []float64{nan,nan,nan,nan,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
+	               Lower:  []float64{nan,nan,nan,nan,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
+			       BandWidth: []float64{nan,nan,nan,nan,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0},
 			      }
 
-	got, err := BB(input, period).Compute(0.0, SMA)
+	got, err := BB(input, period).WithFactor(0.0).WithType(SMA).ComputeBands()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
-	_, err = sliceAlmostEqual(got.mean, want.mean, ACC, "BB.mean: ")
+	_, err = sliceAlmostEqual(got.Mean, want.Mean, ACC, "BB.mean: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.upper, want.upper, ACC, "BB.upper: ")
+	_, err = sliceAlmostEqual(got.Upper, want.Upper, ACC, "BB.Upper: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.lower, want.lower, ACC, "BB.lower: ")
+	_, err = sliceAlmostEqual(got.Lower, want.Lower, ACC, "BB.Lower: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.band_width, want.band_width, ACC, "BB.band_width: ")
+	_, err = sliceAlmostEqual(got.BandWidth, want.BandWidth, ACC, "BB.BandWidth: ")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -335,29 +400,29 @@ func TestBBPeriod5F0(t *testing.T) {
 func TestBBPeriod5F2(t *testing.T) {
 	input := []float64{90.7043,92.9001,92.9784,91.8021,92.6647,92.6843,92.3021,92.7725,92.5373,92.9490,93.2039,91.0669,89.8318,89.7435,90.3994,90.7387,88.0177,88.0867,88.8439,90.7781,90.5416,91.3894,90.6500}
 	period := 5
-	want := BBands{mean:  []float64{0,0,0,0,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
-			       upper: []float64{0,0,0,0,93.931999,93.445448,93.293910,93.164323,92.918883,93.086592,93.380300,94.009602,94.475378,94.320001,93.387131,91.377300,91.623830,91.685323,91.509657,91.755346,91.626784,92.426023,92.141806},
-			       lower: []float64{0,0,0,0,90.487841,91.766392,91.678730,91.725957,92.265477,92.211488,92.125620,91.002238,89.360182,88.398039,88.311069,89.334820,87.868610,87.109077,86.924903,86.830694,86.880416,87.429857,88.739394},
-			       band_width: []float64{0,0,0,0,3.444157,1.679055,1.615180,1.438365,0.653407,0.875104,1.254680,3.007364,5.115196,5.921961,5.076062,2.042480,3.755220,4.576245,4.584755,4.924652,4.746368,4.996166,3.402412},
+	want := BBands{Mean:  []float64{nan,nan,nan,nan,92.209920,92.605920,92.486320,92.445140,92.592180,92.649040,92.752960,92.505920,91.917780,91.359020,90.849100,90.356060,89.746220,89.397200,89.217280,89.293020,89.253600,89.927940,90.440600},
+			       Upper: []float64{nan,nan,nan,nan,93.931999,93.445448,93.293910,93.164323,92.918883,93.086592,93.380300,94.009602,94.475378,94.320001,93.387131,91.377300,91.623830,91.685323,91.509657,91.755346,91.626784,92.426023,92.141806},
+			       Lower: []float64{nan,nan,nan,nan,90.487841,91.766392,91.678730,91.725957,92.265477,92.211488,92.125620,91.002238,89.360182,88.398039,88.311069,89.334820,87.868610,87.109077,86.924903,86.830694,86.880416,87.429857,88.739394},
+			       BandWidth: []float64{nan,nan,nan,nan,3.444157,1.679055,1.615180,1.438365,0.653407,0.875104,1.254680,3.007364,5.115196,5.921961,5.076062,2.042480,3.755220,4.576245,4.584755,4.924652,4.746368,4.996166,3.402412},
 			      }
 
-	got, err := BB(input, period).Compute(2.0, SMA)
+	got, err := BB(input, period).WithFactor(2.0).WithType(SMA).ComputeBands()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
-	_, err = sliceAlmostEqual(got.mean, want.mean, ACC, "BB.mean: ")
+	_, err = sliceAlmostEqual(got.Mean, want.Mean, ACC, "BB.mean: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.upper, want.upper, ACC, "BB.upper: ")
+	_, err = sliceAlmostEqual(got.Upper, want.Upper, ACC, "BB.Upper: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.lower, want.lower, ACC, "BB.lower: ")
+	_, err = sliceAlmostEqual(got.Lower, want.Lower, ACC, "BB.Lower: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.band_width, want.band_width, ACC, "BB.band_width: ")
+	_, err = sliceAlmostEqual(got.BandWidth, want.BandWidth, ACC, "BB.BandWidth: ")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -366,29 +431,29 @@ func TestBBPeriod5F2(t *testing.T) {
 func TestBBPeriod20F2(t *testing.T) {
 	input := []float64{90.7043,92.9001,92.9784,91.8021,92.6647,92.6843,92.3021,92.7725,92.5373,92.9490,93.2039,91.0669,89.8318,89.7435,90.3994,90.7387,88.0177,88.0867,88.8439,90.7781,90.5416,91.3894,90.6500}
 	period := 20
-	want := BBands{mean:  []float64{0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,91.250270,91.242135,91.166600,91.050180},
-			       upper: []float64{0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,94.534271,94.532306,94.369251,94.148503},
-			       lower: []float64{0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,87.966269,87.951964,87.963949,87.951857},
-			       band_width: []float64{0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,6.568003,6.580342,6.405301,6.196647},
+	want := BBands{Mean:  []float64{nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,91.250270,91.242135,91.166600,91.050180},
+			       Upper: []float64{nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,94.534271,94.532306,94.369251,94.148503},
+			       Lower: []float64{nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,87.966269,87.951964,87.963949,87.951857},
+			       BandWidth: []float64{nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,nan,6.568003,6.580342,6.405301,6.196647},
 			      }
 
-	got, err := BB(input, period).Compute(2.0, SMA)
+	got, err := BB(input, period).WithFactor(2.0).WithType(SMA).ComputeBands()
 	if err != nil {
 		t.Fatalf("Unexpected error occurred: %v ", err)
 	}
-	_, err = sliceAlmostEqual(got.mean, want.mean, ACC, "BB.mean: ")
+	_, err = sliceAlmostEqual(got.Mean, want.Mean, ACC, "BB.mean: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.upper, want.upper, ACC, "BB.upper: ")
+	_, err = sliceAlmostEqual(got.Upper, want.Upper, ACC, "BB.Upper: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.lower, want.lower, ACC, "BB.lower: ")
+	_, err = sliceAlmostEqual(got.Lower, want.Lower, ACC, "BB.Lower: ")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = sliceAlmostEqual(got.band_width, want.band_width, ACC, "BB.band_width: ")
+	_, err = sliceAlmostEqual(got.BandWidth, want.BandWidth, ACC, "BB.BandWidth: ")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -397,7 +462,7 @@ func TestBBPeriod20F2(t *testing.T) {
 func TestRslPeriod1(t *testing.T) {
 	input := []float64{2,4,6,8,12,14,16,18,20}
 	period := 1
-	want := []float64{0,4./3,6./5,8./7,12./10,14./13,16./15,18./17,20./19}
+	want := []float64{nan,4./3,6./5,8./7,12./10,14./13,16./15,18./17,20./19}
 
 	got, err := RSL(input, period).Compute()
 	if err != nil {
@@ -412,7 +477,7 @@ func TestRslPeriod1(t *testing.T) {
 func TestRslPeriod2(t *testing.T) {
 	input := []float64{2,4,6,8,12,14,16,18,20}
 	period := 2
-	want := []float64{0,0,6./4,24./18,36./26,42./34,48./42,54./48,60./54}
+	want := []float64{nan,nan,6./4,24./18,36./26,42./34,48./42,54./48,60./54}
 
 	got, err := RSL(input, period).Compute()
 	if err != nil {
@@ -427,7 +492,7 @@ func TestRslPeriod2(t *testing.T) {
 func TestRslPeriod3(t *testing.T) {
 	input := []float64{2,4,6,8,12,14,16,18,20}
 	period := 3
-	want := []float64{0,0,0,32./20,48./30,56./40,64./50,72./60,80./68}
+	want := []float64{nan,nan,nan,32./20,48./30,56./40,64./50,72./60,80./68}
 
 	got, err := RSL(input, period).Compute()
 	if err != nil {
@@ -443,7 +508,7 @@ func TestRslPeriod3(t *testing.T) {
 func TestRslPeriod8(t *testing.T) {
 	input := []float64{2,4,6,8,12,14,16,18,20}
 	period := 8
-	want := []float64{0,0,0,0,0,0,0,0,180./100}
+	want := []float64{nan,nan,nan,nan,nan,nan,nan,nan,180./100}
 
 	got, err := RSL(input, period).Compute()
 	if err != nil {
@@ -454,4 +519,42 @@ func TestRslPeriod8(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-}
\ No newline at end of file
+}
+func TestIndicatorContract(t *testing.T) {
+	all := map[string]Indicator{
+		"MA":   MA(nil, TESTPERIOD),
+		"RSI":  RSI(nil, TESTPERIOD),
+		"ROC":  ROC(nil, TESTPERIOD),
+		"RSL":  RSL(nil, TESTPERIOD),
+		"BB":   BB(nil, TESTPERIOD),
+		"TR":   TR(BarHistory{}),
+		"ATR":  ATR(BarHistory{}, TESTPERIOD),
+		"ATRP": ATRP(BarHistory{}, TESTPERIOD),
+		"ADX":  ADX(BarHistory{}, TESTPERIOD),
+		"Last": Last(BarHistory{}, Close),
+	}
+	for name, ind := range all {
+		if _, err := ind.Compute(); err == nil {
+			t.Errorf("%s: want error without input", name)
+		}
+		ind.SetInput(&TestBars)
+		got, err := ind.Compute()
+		if err != nil {
+			t.Fatalf("%s: unexpected error occurred: %v ", name, err)
+		}
+		if len(got) != TestBars.Len() {
+			t.Errorf("%s: expected %d values, got %d", name, TestBars.Len(), len(got))
+		}
+		last, err := Latest(ind)
+		if err != nil || last != got[len(got)-1] {
+			t.Errorf("%s: expected latest value %v, got %v, %v", name, got[len(got)-1], last, err)
+		}
+	}
+}
+
+func TestADXNotEnoughBars(t *testing.T) {
+	_, err := ADX(TestBars.Sub(0, 2*TESTPERIOD-1), TESTPERIOD).Compute()
+	if err == nil {
+		t.Errorf("Want error for not enough bars")
+	}
+}
@@ -1,5 +1,11 @@
 package indicators
 
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
 func Last(bars BarHistory, valueType LastValueType) *LastValue {
 	return &LastValue{
 		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
@@ -22,18 +28,48 @@ type LastValue struct {
 	ValueType LastValueType
 }
 
-func (ind *LastValue) Compute() float64 {
+// Compute returns the column of the bars selected by ValueType, its last element is the
+// last value.
+func (ind *LastValue) Compute() ([]float64, error) {
+	var res []float64
 	switch ind.ValueType {
 	case Open:
-		return ind.Input.Open[len(ind.Input.Open)-1]
+		res = append(res, ind.Input.Open...)
 	case High:
-		return ind.Input.High[len(ind.Input.High)-1]
+		res = append(res, ind.Input.High...)
 	case Low:
-		return ind.Input.Low[len(ind.Input.Low)-1]
+		res = append(res, ind.Input.Low...)
 	case Close:
-		return ind.Input.Close[len(ind.Input.Close)-1]
+		res = append(res, ind.Input.Close...)
 	case Volume:
-		return float64(ind.Input.Volume[len(ind.Input.Volume)-1])
+		for _, v := range ind.Input.Volume {
+			res = append(res, float64(v))
+		}
+	default:
+		return nil, fmt.Errorf("unknown value type: %d", ind.ValueType)
 	}
-	return 0
+	if len(res) == 0 {
+		return nil, errors.New("no bars")
+	}
+	return res, nil
 }
+
+// Update returns the value of the bar selected by ValueType.
+func (ind *LastValue) Update(bar Bar) float64 {
+	switch ind.ValueType {
+	case Open:
+		return bar.Open
+	case High:
+		return bar.High
+	case Low:
+		return bar.Low
+	case Close:
+		return bar.Close
+	case Volume:
+		return float64(bar.Volume)
+	}
+	return math.NaN()
+}
+
+// Reset does nothing, LastValue keeps no state.
+func (ind *LastValue) Reset() {}
@@ -0,0 +1,138 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the linear regression.
+const (
+	LinRegSlope         = "slope"
+	LinRegIntercept     = "intercept"
+	LinRegR2            = "r2"
+	LinRegStdErr        = "stderr"
+	LinRegAdjustedSlope = "adjustedSlope"
+)
+
+// RegressionLines holds the outputs of the linear regression. AdjustedSlope is the slope
+// times R², which ranks steady trends above erratic ones.
+type RegressionLines struct {
+	Slope         []float64
+	Intercept     []float64
+	R2            []float64
+	StdErr        []float64
+	AdjustedSlope []float64
+}
+
+// LinearRegression fits a line by least squares to the last Period values, with x running
+// from 0 at the oldest to Period-1 at the current value. Slope is the change per bar,
+// Intercept the value of the line at the oldest bar, R2 the coefficient of determination
+// and StdErr the standard error of the estimate. R2 is 0 if the values did not change and
+// StdErr requires a period of at least 3. With Log the logarithm of the input is fitted,
+// the slope is then the continuous growth rate per bar. The first Period-1 values are NaN.
+type LinearRegression struct {
+	TimeSeriesIndicator
+	Log bool
+}
+
+func LinReg(input []float64, period int) *LinearRegression {
+	return &LinearRegression{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+// WithLog fits the logarithm of the input, e.g. for exponential momentum.
+func (ind *LinearRegression) WithLog() *LinearRegression {
+	ind.Log = true
+	return ind
+}
+
+// Compute returns the slope.
+func (ind *LinearRegression) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.Slope, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *LinearRegression) Outputs() []string {
+	return []string{LinRegSlope, LinRegIntercept, LinRegR2, LinRegStdErr, LinRegAdjustedSlope}
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *LinearRegression) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{
+		LinRegSlope:         res.Slope,
+		LinRegIntercept:     res.Intercept,
+		LinRegR2:            res.R2,
+		LinRegStdErr:        res.StdErr,
+		LinRegAdjustedSlope: res.AdjustedSlope,
+	}, nil
+}
+
+// ComputeLines returns all lines.
+func (ind *LinearRegression) ComputeLines() (RegressionLines, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return RegressionLines{}, err
+	}
+	if ind.Period < 2 {
+		return RegressionLines{}, fmt.Errorf("invalid period: %d < 2", ind.Period)
+	}
+	y := ind.Input
+	if ind.Log {
+		y = make([]float64, len(ind.Input))
+		for i, v := range ind.Input {
+			if v <= 0 {
+				return RegressionLines{}, fmt.Errorf("logarithm of non-positive value %v at index %d", v, i)
+			}
+			y[i] = math.Log(v)
+		}
+	}
+
+	n := len(y)
+	res := RegressionLines{
+		Slope:         make([]float64, n),
+		Intercept:     make([]float64, n),
+		R2:            make([]float64, n),
+		StdErr:        make([]float64, n),
+		AdjustedSlope: make([]float64, n),
+	}
+	p := float64(ind.Period)
+	meanX := (p - 1) / 2
+	sxx := p * (p*p - 1) / 12
+	for i := 0; i < n; i++ {
+		if i < ind.Period-1 {
+			res.Slope[i], res.Intercept[i], res.R2[i], res.StdErr[i], res.AdjustedSlope[i] =
+				math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		window := y[i-ind.Period+1 : i+1]
+		meanY := Mean(window)
+		sxy, syy := 0.0, 0.0
+		for x, v := range window {
+			sxy += (float64(x) - meanX) * (v - meanY)
+			syy += (v - meanY) * (v - meanY)
+		}
+		slope := sxy / sxx
+		sse := math.Max(syy-slope*sxy, 0)
+
+		res.Slope[i] = slope
+		res.Intercept[i] = meanY - slope*meanX
+		if syy != 0 {
+			res.R2[i] = 1 - sse/syy
+		}
+		res.StdErr[i] = math.NaN()
+		if ind.Period > 2 {
+			res.StdErr[i] = math.Sqrt(sse / (p - 2))
+		}
+		res.AdjustedSlope[i] = slope * res.R2[i]
+	}
+	return res, nil
+}
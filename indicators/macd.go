@@ -0,0 +1,167 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the MACD and the PPO.
+const (
+	MACDLine      = "macd"
+	MACDSignal    = "signal"
+	MACDHistogram = "histogram"
+)
+
+// MACDLines holds the outputs of the MACD and the PPO.
+type MACDLines struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// MovingAverageConvergenceDivergence is the difference of a fast and a slow moving average
+// of the input, the signal line is a moving average of that difference. The averages are
+// EMAs by default. The MACD line is NaN for the first Slow-1 values, the signal line and the
+// histogram for the first Slow+Signal-2 values.
+type MovingAverageConvergenceDivergence struct {
+	TimeSeriesIndicator
+	Fast   int
+	Slow   int
+	Signal int
+	Type   maType
+}
+
+func MACD(input []float64, fast, slow, signal int) *MovingAverageConvergenceDivergence {
+	return &MovingAverageConvergenceDivergence{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, slow),
+		Fast:                fast,
+		Slow:                slow,
+		Signal:              signal,
+		Type:                EMA,
+	}
+}
+
+// WithType sets the type of the moving averages.
+func (ind *MovingAverageConvergenceDivergence) WithType(matype maType) *MovingAverageConvergenceDivergence {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns the MACD line.
+func (ind *MovingAverageConvergenceDivergence) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.MACD, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *MovingAverageConvergenceDivergence) Outputs() []string {
+	return []string{MACDLine, MACDSignal, MACDHistogram}
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *MovingAverageConvergenceDivergence) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeLines returns the MACD line, the signal line and the histogram.
+func (ind *MovingAverageConvergenceDivergence) ComputeLines() (MACDLines, error) {
+	return ind.lines(func(fast, slow float64) float64 { return fast - slow })
+}
+
+// lines computes the oscillator given by diff from the fast and slow average.
+func (ind *MovingAverageConvergenceDivergence) lines(diff func(fast, slow float64) float64) (MACDLines, error) {
+	if ind.Fast <= 0 || ind.Slow <= ind.Fast || ind.Signal <= 0 {
+		return MACDLines{}, fmt.Errorf("invalid periods: fast %d, slow %d, signal %d", ind.Fast, ind.Slow, ind.Signal)
+	}
+	err := CheckInput(ind.Input, ind.Slow+ind.Signal-1)
+	if err != nil {
+		return MACDLines{}, err
+	}
+
+	fast, err := MA(ind.Input, ind.Fast).WithType(ind.Type).WithVolume(ind.Volume).Compute()
+	if err != nil {
+		return MACDLines{}, err
+	}
+	slow, err := MA(ind.Input, ind.Slow).WithType(ind.Type).WithVolume(ind.Volume).Compute()
+	if err != nil {
+		return MACDLines{}, err
+	}
+
+	res := MACDLines{
+		MACD:      make([]float64, len(ind.Input)),
+		Histogram: make([]float64, len(ind.Input)),
+	}
+	start := ind.Slow - 1
+	for i := range ind.Input {
+		res.MACD[i] = math.NaN()
+		if i >= start {
+			res.MACD[i] = diff(fast[i], slow[i])
+		}
+	}
+
+	// the signal line starts with the first defined value of the MACD line
+	res.Signal, err = smooth(res.MACD, ind.Volume, start, ind.Signal, ind.Type)
+	if err != nil {
+		return MACDLines{}, err
+	}
+	for i := range ind.Input {
+		res.Histogram[i] = res.MACD[i] - res.Signal[i]
+	}
+	return res, nil
+}
+
+func (lines MACDLines) outputs() map[string][]float64 {
+	return map[string][]float64{
+		MACDLine:      lines.MACD,
+		MACDSignal:    lines.Signal,
+		MACDHistogram: lines.Histogram,
+	}
+}
+
+// PercentagePriceOscillator is the MACD in percent of the slow moving average, which makes
+// it comparable across assets with different prices.
+type PercentagePriceOscillator struct {
+	MovingAverageConvergenceDivergence
+}
+
+func PPO(input []float64, fast, slow, signal int) *PercentagePriceOscillator {
+	return &PercentagePriceOscillator{
+		MovingAverageConvergenceDivergence: *MACD(input, fast, slow, signal),
+	}
+}
+
+// WithType sets the type of the moving averages.
+func (ind *PercentagePriceOscillator) WithType(matype maType) *PercentagePriceOscillator {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns the PPO line.
+func (ind *PercentagePriceOscillator) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.MACD, nil
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *PercentagePriceOscillator) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeLines returns the PPO line, its signal line and the histogram.
+func (ind *PercentagePriceOscillator) ComputeLines() (MACDLines, error) {
+	return ind.lines(func(fast, slow float64) float64 { return (fast - slow) / slow * 100 })
+}
@@ -0,0 +1,107 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// emaRef is a plain EMA seeded with the first value.
+func emaRef(input []float64, period int) []float64 {
+	alpha := 2.0 / float64(period+1)
+	res := []float64{input[0]}
+	for _, x := range input[1:] {
+		prev := res[len(res)-1]
+		res = append(res, alpha*x+(1-alpha)*prev)
+	}
+	return res
+}
+
+func TestMACD(t *testing.T) {
+	fast, slow, signal := 3, 6, 4
+	got, err := MACD(TestBars.Close, fast, slow, signal).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	emaFast, emaSlow := emaRef(TestBars.Close, fast), emaRef(TestBars.Close, slow)
+	line := make([]float64, TestBars.Len())
+	for i := range line {
+		line[i] = nan
+		if i >= slow-1 {
+			line[i] = emaFast[i] - emaSlow[i]
+		}
+	}
+	sig := emaRef(line[slow-1:], signal)
+	wantSignal := make([]float64, TestBars.Len())
+	wantHist := make([]float64, TestBars.Len())
+	for i := range wantSignal {
+		wantSignal[i], wantHist[i] = nan, nan
+		if i >= slow+signal-2 {
+			wantSignal[i] = sig[i-slow+1]
+			wantHist[i] = line[i] - wantSignal[i]
+		}
+	}
+
+	if _, err := sliceAlmostEqual(got.MACD, line, 1e-9, "MACD: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.Signal, wantSignal, 1e-9, "signal: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.Histogram, wantHist, 1e-9, "histogram: "); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMACDConstantInput(t *testing.T) {
+	input := make([]float64, 40)
+	for i := range input {
+		input[i] = 100
+	}
+	for _, matype := range []maType{SMA, EMA, LWMA, WILDER} {
+		got, err := MACD(input, 12, 26, 9).WithType(matype).ComputeLines()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if !math.IsNaN(got.MACD[24]) || !math.IsNaN(got.Signal[32]) {
+			t.Errorf("type %d: expected NaN during warm-up", matype)
+		}
+		if math.Abs(got.MACD[25]) > 1e-9 || math.Abs(got.Histogram[33]) > 1e-9 {
+			t.Errorf("type %d: expected 0 for constant input, got %v, %v", matype, got.MACD[25], got.Histogram[33])
+		}
+	}
+}
+
+func TestMACDInvalid(t *testing.T) {
+	for _, periods := range [][3]int{{0, 26, 9}, {26, 12, 9}, {12, 26, 0}, {12, 26, 9}} {
+		if _, err := MACD(TestBars.Close, periods[0], periods[1], periods[2]).Compute(); err == nil {
+			t.Errorf("Want error for periods %v and %d bars", periods, TestBars.Len())
+		}
+	}
+	// the shortest valid input
+	if _, err := MACD(TestBars.Close[:9], 3, 6, 3).Compute(); err != nil {
+		t.Errorf("Unexpected error occurred: %v ", err)
+	}
+}
+
+func TestPPO(t *testing.T) {
+	fast, slow, signal := 3, 6, 4
+	got, err := PPO(TestBars.Close, fast, slow, signal).ComputeAll()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	emaFast, emaSlow := emaRef(TestBars.Close, fast), emaRef(TestBars.Close, slow)
+	for i := slow - 1; i < TestBars.Len(); i++ {
+		want := (emaFast[i] - emaSlow[i]) / emaSlow[i] * 100
+		if math.Abs(got[MACDLine][i]-want) > 1e-9 {
+			t.Fatalf("PPO at index %d: %v != %v", i, got[MACDLine][i], want)
+		}
+	}
+
+	line := Output(PPO(nil, fast, slow, signal), MACDLine)
+	line.SetInput(&TestBars)
+	last, err := Latest(line)
+	if err != nil || last != got[MACDLine][TestBars.Len()-1] {
+		t.Errorf("Expected %v, got %v, %v", got[MACDLine][TestBars.Len()-1], last, err)
+	}
+}
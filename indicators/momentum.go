@@ -0,0 +1,75 @@
+package indicators
+
+import "math"
+
+// Momentum is the difference of the input and the input Period values ago, the absolute
+// counterpart of RateOfChange. The first Period values are NaN.
+type Momentum struct {
+	TimeSeriesIndicator
+}
+
+func MOM(input []float64, period int) *Momentum {
+	return &Momentum{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *Momentum) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		if i < ind.Period {
+			res[i] = math.NaN()
+			continue
+		}
+		res[i] = ind.Input[i] - ind.Input[i-ind.Period]
+	}
+	return res, nil
+}
+
+// ChandeMomentumOscillator is the sum of the gains minus the sum of the losses of the last
+// Period changes, divided by the sum of both. It ranges from -100 to 100 and is 0 if the
+// input did not change. Unlike the RSI the changes are not smoothed. The first Period values
+// are NaN.
+type ChandeMomentumOscillator struct {
+	TimeSeriesIndicator
+}
+
+func CMO(input []float64, period int) *ChandeMomentumOscillator {
+	return &ChandeMomentumOscillator{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *ChandeMomentumOscillator) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		if i < ind.Period {
+			res[i] = math.NaN()
+			continue
+		}
+		gains, losses := 0.0, 0.0
+		for j := i - ind.Period + 1; j <= i; j++ {
+			change := ind.Input[j] - ind.Input[j-1]
+			if change > 0 {
+				gains += change
+			} else {
+				losses -= change
+			}
+		}
+		if gains+losses == 0 {
+			continue
+		}
+		res[i] = 100 * (gains - losses) / (gains + losses)
+	}
+	return res, nil
+}
@@ -1,6 +1,9 @@
 package indicators
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type maType uint
 
@@ -9,10 +12,36 @@ const (
 	EMA
 	LWMA
 	WILDER
+	// DEMA is the double exponential moving average 2*EMA - EMA(EMA).
+	DEMA
+	// TEMA is the triple exponential moving average 3*EMA - 3*EMA(EMA) + EMA(EMA(EMA)).
+	TEMA
+	// HMA is the Hull moving average LWMA(2*LWMA(n/2) - LWMA(n), sqrt(n)).
+	HMA
+	// KAMA is Kaufman's adaptive moving average, which follows the input the faster the
+	// more efficient its trend is.
+	KAMA
+	// ALMA is the Arnaud Legoux moving average, weighted by a Gaussian centered close to
+	// the most recent value.
+	ALMA
+	// T3 is Tillson's moving average, a triple smoothed generalized DEMA.
+	T3
+	// VWMA is the volume weighted moving average. It requires Volume.
+	VWMA
 )
 
 type MovingAverage struct {
 	TimeSeriesIndicator
+	// Type is the moving average computed by Compute and Update, defaults to SMA.
+	Type maType
+
+	window  ringBuffer
+	volumes ringBuffer
+	weights []float64
+	// stages are the moving averages the streaming DEMA, TEMA, T3 and HMA are composed of.
+	stages []*MovingAverage
+	sum    float64
+	last   float64
 }
 
 func MA(input []float64, period int) *MovingAverage {
@@ -21,14 +50,27 @@ func MA(input []float64, period int) *MovingAverage {
 	}
 }
 
-func (ind *MovingAverage) Compute(matype maType) ([]float64, error) {
+// WithVolume sets the volume belonging to the input, it is required by VWMA.
+func (ind *MovingAverage) WithVolume(volume []float64) *MovingAverage {
+	ind.Volume = volume
+	return ind
+}
+
+// WithType sets the type of the moving average, e.g. MA(input, 10).WithType(EMA).
+func (ind *MovingAverage) WithType(matype maType) *MovingAverage {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns the moving average of type Type.
+func (ind *MovingAverage) Compute() ([]float64, error) {
 	err := CheckInput(ind.Input, ind.Period)
 	if err != nil {
 		return nil, err
 	}
 
 	var weights []float64
-	switch matype {
+	switch ind.Type {
 	case SMA:
 		weights = ind.computeSwmaWeights(ind.Period)
 		return ind.wma(ind.Input, weights), nil
@@ -39,8 +81,26 @@ func (ind *MovingAverage) Compute(matype maType) ([]float64, error) {
 		return ind.ema(ind.Input, ind.Period), nil
 	case WILDER:
 		return ind.wilder(ind.Input, ind.Period), nil
+	case DEMA:
+		return ind.dema(ind.Input, ind.Period), nil
+	case TEMA:
+		return ind.tema(ind.Input, ind.Period), nil
+	case HMA:
+		return ind.hma(ind.Input, ind.Period), nil
+	case KAMA:
+		return ind.kama(ind.Input, ind.Period), nil
+	case ALMA:
+		weights = ind.computeAlmaWeights(ind.Period)
+		return ind.wma(ind.Input, weights), nil
+	case T3:
+		return ind.t3(ind.Input, ind.Period), nil
+	case VWMA:
+		if len(ind.Volume) != len(ind.Input) {
+			return nil, fmt.Errorf("VWMA requires the volume of every value")
+		}
+		return ind.vwma(ind.Input, ind.Volume, ind.Period), nil
 	default:
-		return nil, fmt.Errorf("moving average type not yet implemented.: %d", matype)
+		return nil, fmt.Errorf("moving average type not yet implemented.: %d", ind.Type)
 	}
 }
 
@@ -77,10 +137,12 @@ func (ind *MovingAverage) ema(input []float64, period int) []float64 {
 func (ind *MovingAverage) wma(input []float64, weights []float64) []float64 {
 	res := make([]float64, len(input))
 	for i := range input {
-		if i+1 >= len(weights) {
-			for j, weight := range weights {
-				res[i] += input[i-j] * weight
-			}
+		if i+1 < len(weights) {
+			res[i] = math.NaN()
+			continue
+		}
+		for j, weight := range weights {
+			res[i] += input[i-j] * weight
 		}
 	}
 	return res
@@ -88,6 +150,9 @@ func (ind *MovingAverage) wma(input []float64, weights []float64) []float64 {
 
 func (ind *MovingAverage) wilder(input []float64, period int) []float64 {
 	res := make([]float64, len(input))
+	for i := 0; i < period-1; i++ {
+		res[i] = math.NaN()
+	}
 
 	// Calculate the first WilderMA value
 	sum := 0.0
@@ -103,3 +168,150 @@ func (ind *MovingAverage) wilder(input []float64, period int) []float64 {
 
 	return res
 }
+
+// Update adds the close and the volume of the bar and returns the current value of the
+// moving average.
+func (ind *MovingAverage) Update(bar Bar) float64 {
+	return ind.update(bar.Close, float64(bar.Volume))
+}
+
+// UpdateValue adds the value and returns the current value of the moving average of type
+// Type. Values within the warm-up period are the same as the ones of Compute. As the value
+// carries no volume, VWMA weights all values equally; use Update to weight them by volume.
+func (ind *MovingAverage) UpdateValue(value float64) float64 {
+	return ind.update(value, 1)
+}
+
+func (ind *MovingAverage) update(value, volume float64) float64 {
+	if ind.Period <= 0 {
+		return math.NaN()
+	}
+	if ind.window.values == nil {
+		size := ind.Period
+		if ind.Type == KAMA {
+			// the efficiency ratio looks back Period changes
+			size++
+		}
+		ind.window = newRingBuffer(size)
+	}
+	ind.window.push(value)
+
+	switch ind.Type {
+	case SMA, LWMA, ALMA:
+		if ind.weights == nil {
+			switch ind.Type {
+			case SMA:
+				ind.weights = ind.computeSwmaWeights(ind.Period)
+			case LWMA:
+				ind.weights = ind.computeLwmaWeights(ind.Period)
+			default:
+				ind.weights = ind.computeAlmaWeights(ind.Period)
+			}
+		}
+		if !ind.window.full() {
+			return math.NaN()
+		}
+		res := 0.0
+		for j, weight := range ind.weights {
+			res += ind.window.at(j) * weight
+		}
+		ind.last = res
+	case EMA:
+		if ind.window.count == 1 {
+			ind.last = value
+		} else {
+			alpha := 2.0 / (float64(ind.Period) + 1)
+			ind.last = ind.last + alpha*(value-ind.last)
+		}
+	case WILDER:
+		if ind.window.count <= ind.Period {
+			ind.sum += value
+			if ind.window.count < ind.Period {
+				return math.NaN()
+			}
+			ind.last = ind.sum / float64(ind.Period)
+		} else {
+			ind.last = ind.last + (value-ind.last)/float64(ind.Period)
+		}
+	case DEMA, TEMA, T3:
+		if ind.stages == nil {
+			n := map[maType]int{DEMA: 2, TEMA: 3, T3: 6}[ind.Type]
+			for k := 0; k < n; k++ {
+				ind.stages = append(ind.stages, MA(nil, ind.Period).WithType(EMA))
+			}
+		}
+		// nested EMAs of the value
+		e := make([]float64, len(ind.stages))
+		for k, stage := range ind.stages {
+			value = stage.UpdateValue(value)
+			e[k] = value
+		}
+		switch ind.Type {
+		case DEMA:
+			ind.last = 2*e[0] - e[1]
+		case TEMA:
+			ind.last = 3*e[0] - 3*e[1] + e[2]
+		default:
+			ind.last = t3Value(e)
+		}
+	case HMA:
+		if ind.stages == nil {
+			half, root := hmaPeriods(ind.Period)
+			ind.stages = []*MovingAverage{
+				MA(nil, half).WithType(LWMA),
+				MA(nil, ind.Period).WithType(LWMA),
+				MA(nil, root).WithType(LWMA),
+			}
+		}
+		fast := ind.stages[0].UpdateValue(value)
+		slow := ind.stages[1].UpdateValue(value)
+		if math.IsNaN(slow) {
+			return math.NaN()
+		}
+		ind.last = ind.stages[2].UpdateValue(2*fast - slow)
+	case KAMA:
+		switch {
+		case ind.window.count < ind.Period:
+			return math.NaN()
+		case ind.window.count == ind.Period:
+			ind.last = value
+		default:
+			change := math.Abs(ind.window.at(0) - ind.window.at(ind.Period))
+			volatility := 0.0
+			for j := 0; j < ind.Period; j++ {
+				volatility += math.Abs(ind.window.at(j) - ind.window.at(j+1))
+			}
+			ind.last = ind.last + kamaSmoothing(change, volatility)*(value-ind.last)
+		}
+	case VWMA:
+		if ind.volumes.values == nil {
+			ind.volumes = newRingBuffer(ind.Period)
+		}
+		ind.volumes.push(volume)
+		if !ind.window.full() {
+			return math.NaN()
+		}
+		pv, v := 0.0, 0.0
+		for j := 0; j < ind.Period; j++ {
+			pv += ind.window.at(j) * ind.volumes.at(j)
+			v += ind.volumes.at(j)
+		}
+		if v == 0 {
+			return math.NaN()
+		}
+		ind.last = pv / v
+	default:
+		return math.NaN()
+	}
+	return ind.last
+}
+
+// Reset clears the state of Update.
+func (ind *MovingAverage) Reset() {
+	ind.window = ringBuffer{}
+	ind.volumes = ringBuffer{}
+	ind.weights = nil
+	ind.stages = nil
+	ind.sum = 0
+	ind.last = 0
+}
@@ -0,0 +1,162 @@
+package indicators
+
+import "math"
+
+// Parameters of the adaptive moving averages.
+const (
+	// kamaFast and kamaSlow are the EMA periods KAMA moves between.
+	kamaFast = 2
+	kamaSlow = 30
+	// almaOffset places the center of the ALMA weights, 1 is the most recent value.
+	almaOffset = 0.85
+	// almaSigma is the width of the window divided by the standard deviation of the weights.
+	almaSigma = 6
+	// t3Factor is the volume factor of T3, 0 yields the triple EMA and 1 the triple DEMA.
+	t3Factor = 0.7
+)
+
+func (ind *MovingAverage) dema(input []float64, period int) []float64 {
+	e1 := ind.ema(input, period)
+	e2 := ind.ema(e1, period)
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = 2*e1[i] - e2[i]
+	}
+	return res
+}
+
+func (ind *MovingAverage) tema(input []float64, period int) []float64 {
+	e1 := ind.ema(input, period)
+	e2 := ind.ema(e1, period)
+	e3 := ind.ema(e2, period)
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = 3*e1[i] - 3*e2[i] + e3[i]
+	}
+	return res
+}
+
+// hmaPeriods returns the periods of the fast and the final LWMA of the HMA.
+func hmaPeriods(period int) (half, root int) {
+	half = period / 2
+	if half < 1 {
+		half = 1
+	}
+	root = int(math.Sqrt(float64(period)))
+	if root < 1 {
+		root = 1
+	}
+	return half, root
+}
+
+func (ind *MovingAverage) hma(input []float64, period int) []float64 {
+	half, root := hmaPeriods(period)
+
+	fast := ind.wma(input, ind.computeLwmaWeights(half))
+	slow := ind.wma(input, ind.computeLwmaWeights(period))
+	raw := make([]float64, len(input)-period+1)
+	for i := range raw {
+		raw[i] = 2*fast[i+period-1] - slow[i+period-1]
+	}
+	hull := ind.wma(raw, ind.computeLwmaWeights(root))
+
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= period-1 {
+			res[i] = hull[i-period+1]
+		}
+	}
+	return res
+}
+
+// kamaSmoothing returns the smoothing constant of KAMA for the net change and the sum of the
+// absolute changes over the period.
+func kamaSmoothing(change, volatility float64) float64 {
+	fastSC := 2.0 / (kamaFast + 1)
+	slowSC := 2.0 / (kamaSlow + 1)
+	// efficiency ratio of the trend
+	er := 0.0
+	if volatility != 0 {
+		er = change / volatility
+	}
+	return math.Pow(er*(fastSC-slowSC)+slowSC, 2)
+}
+
+func (ind *MovingAverage) kama(input []float64, period int) []float64 {
+	res := make([]float64, len(input))
+	for i := 0; i < period-1; i++ {
+		res[i] = math.NaN()
+	}
+	res[period-1] = input[period-1]
+	for i := period; i < len(input); i++ {
+		change := math.Abs(input[i] - input[i-period])
+		volatility := 0.0
+		for j := i - period + 1; j <= i; j++ {
+			volatility += math.Abs(input[j] - input[j-1])
+		}
+		res[i] = res[i-1] + kamaSmoothing(change, volatility)*(input[i]-res[i-1])
+	}
+	return res
+}
+
+// computeAlmaWeights returns the Gaussian weights of the ALMA, the first weight belongs
+// to the most recent value like in computeLwmaWeights.
+func (ind *MovingAverage) computeAlmaWeights(period int) []float64 {
+	m := almaOffset * float64(period-1)
+	s := float64(period) / almaSigma
+	weights := make([]float64, period)
+	sum := 0.0
+	for i := range weights {
+		j := float64(period - 1 - i)
+		weights[i] = math.Exp(-(j - m) * (j - m) / (2 * s * s))
+		sum += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	return weights
+}
+
+// t3Value combines the third to sixth of six nested EMAs e of the input.
+func t3Value(e []float64) float64 {
+	a := t3Factor
+	c1 := -a * a * a
+	c2 := 3*a*a + 3*a*a*a
+	c3 := -6*a*a - 3*a - 3*a*a*a
+	c4 := 1 + 3*a + a*a*a + 3*a*a
+	return c1*e[5] + c2*e[4] + c3*e[3] + c4*e[2]
+}
+
+func (ind *MovingAverage) t3(input []float64, period int) []float64 {
+	e := make([][]float64, 6)
+	e[0] = ind.ema(input, period)
+	for k := 1; k < len(e); k++ {
+		e[k] = ind.ema(e[k-1], period)
+	}
+
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = t3Value([]float64{e[0][i], e[1][i], e[2][i], e[3][i], e[4][i], e[5][i]})
+	}
+	return res
+}
+
+func (ind *MovingAverage) vwma(input, volume []float64, period int) []float64 {
+	res := make([]float64, len(input))
+	for i := range res {
+		res[i] = math.NaN()
+		if i < period-1 {
+			continue
+		}
+		pv, v := 0.0, 0.0
+		for j := i - period + 1; j <= i; j++ {
+			pv += input[j] * volume[j]
+			v += volume[j]
+		}
+		if v != 0 {
+			res[i] = pv / v
+		}
+	}
+	return res
+}
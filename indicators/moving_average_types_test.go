@@ -0,0 +1,159 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+var advancedTypes = []maType{DEMA, TEMA, HMA, KAMA, ALMA, T3, VWMA}
+
+func TestAdvancedMAConstantInput(t *testing.T) {
+	input := make([]float64, 40)
+	volume := make([]float64, 40)
+	for i := range input {
+		input[i] = 42
+		volume[i] = float64(100 + i)
+	}
+	for _, matype := range advancedTypes {
+		got, err := MA(input, 9).WithType(matype).WithVolume(volume).Compute()
+		if err != nil {
+			t.Fatalf("type %d: unexpected error occurred: %v ", matype, err)
+		}
+		for i := 20; i < len(got); i++ {
+			if math.Abs(got[i]-42) > 1e-9 {
+				t.Fatalf("type %d: expected 42 at index %d, got %v", matype, i, got[i])
+			}
+		}
+	}
+}
+
+func TestDemaTema(t *testing.T) {
+	e1 := emaRef(TestBars.Close, TESTPERIOD)
+	e2 := emaRef(e1, TESTPERIOD)
+	e3 := emaRef(e2, TESTPERIOD)
+	dema := make([]float64, len(e1))
+	tema := make([]float64, len(e1))
+	for i := range e1 {
+		dema[i] = 2*e1[i] - e2[i]
+		tema[i] = 3*e1[i] - 3*e2[i] + e3[i]
+	}
+
+	for matype, want := range map[maType][]float64{DEMA: dema, TEMA: tema} {
+		got, err := MA(TestBars.Close, TESTPERIOD).WithType(matype).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if _, err := sliceAlmostEqual(got, want, 1e-9, fmt.Sprintf("type %d: ", matype)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestT3(t *testing.T) {
+	// T3 applies the generalized DEMA three times
+	gd := func(x []float64) []float64 {
+		e1 := emaRef(x, TESTPERIOD)
+		e2 := emaRef(e1, TESTPERIOD)
+		res := make([]float64, len(x))
+		for i := range x {
+			res[i] = e1[i]*(1+t3Factor) - e2[i]*t3Factor
+		}
+		return res
+	}
+	want := gd(gd(gd(TestBars.Close)))
+
+	got, err := MA(TestBars.Close, TESTPERIOD).WithType(T3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHMA(t *testing.T) {
+	// the Hull moving average of a line has no lag
+	input := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	got, err := MA(input, 4).WithType(HMA).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, nan, 5, 6, 7, 8}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKAMA(t *testing.T) {
+	// a steady trend is fully efficient, KAMA moves with the fast constant
+	input := []float64{1, 2, 3, 4, 5, 6}
+	got, err := MA(input, 3).WithType(KAMA).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	sc := math.Pow(2.0/3, 2)
+	want := []float64{nan, nan, 3, 0, 0, 0}
+	for i := 3; i < len(want); i++ {
+		want[i] = want[i-1] + sc*(input[i]-want[i-1])
+	}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAlmaWeights(t *testing.T) {
+	weights := MA(nil, 9).computeAlmaWeights(9)
+	sum := 0.0
+	peak := 0
+	for i, w := range weights {
+		sum += w
+		if w > weights[peak] {
+			peak = i
+		}
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("Expected weights to sum up to 1, got %v", sum)
+	}
+	// the center is at 0.85*(9-1)=6.8 counted from the oldest value, i.e. lag 1.2
+	if peak != 1 {
+		t.Errorf("Expected the peak at lag 1, got %d", peak)
+	}
+}
+
+func TestVWMA(t *testing.T) {
+	got, err := MA([]float64{1, 2, 3}, 2).WithType(VWMA).WithVolume([]float64{1, 1, 2}).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, 1.5, 8. / 3}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := MA([]float64{1, 2, 3}, 2).WithType(VWMA).Compute(); err == nil {
+		t.Errorf("Want error without volume")
+	}
+}
+
+func TestAdvancedMAInBollingerBands(t *testing.T) {
+	for _, matype := range advancedTypes {
+		bb := BB(nil, TESTPERIOD).WithType(matype)
+		bb.SetInput(&TestBars)
+		got, err := bb.Compute()
+		if err != nil {
+			t.Fatalf("type %d: unexpected error occurred: %v ", matype, err)
+		}
+		want, err := MA(TestBars.Close, TESTPERIOD).WithType(matype).WithVolume(volumeOf(&TestBars)).Compute()
+		if err != nil {
+			t.Fatalf("type %d: unexpected error occurred: %v ", matype, err)
+		}
+		if _, err := sliceAlmostEqual(got, want, 1e-12); err != nil {
+			t.Errorf("type %d: %v", matype, err)
+		}
+	}
+}
+
+func TestUnknownMAType(t *testing.T) {
+	if _, err := MA(TestBars.Close, TESTPERIOD).WithType(maType(99)).Compute(); err == nil {
+		t.Errorf("Want error for unknown type")
+	}
+}
@@ -0,0 +1,93 @@
+package indicators
+
+import "testing"
+
+func TestCCI(t *testing.T) {
+	got, err := CCI(stochBars, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 12.5, 100, 1300. / 14}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUltimateOscillator(t *testing.T) {
+	got, err := UO(stochBars, 1, 2, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, nan, nan, 100 * (4*2./3 + 2*3./5 + 5./8) / 7, 100 * (4*1./2 + 2*3./5 + 4./7) / 7}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := UO(stochBars, 2, 1, 3).Compute(); err == nil {
+		t.Errorf("Want error for unordered periods")
+	}
+}
+
+func TestTRIX(t *testing.T) {
+	ema := emaRef(emaRef(emaRef(TestBars.Close, TESTPERIOD), TESTPERIOD), TESTPERIOD)
+	want := []float64{nan}
+	for i := 1; i < len(ema); i++ {
+		want = append(want, (ema[i]-ema[i-1])/ema[i-1]*100)
+	}
+	got, err := TRIX(TestBars.Close, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := TRIX(nil, TESTPERIOD).Compute(); err == nil {
+		t.Errorf("Want error without input")
+	}
+
+	got, err = TRIX([]float64{0, 0, 0, 2, 2}, 1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, nan, 0}, 1e-12); err != nil {
+		t.Errorf("zero EMA: %v", err)
+	}
+}
+
+func TestAwesomeOscillator(t *testing.T) {
+	got, err := AO(stochBars, 2, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 10.25 - 29.5/3, 10.75 - 32./3, 12.25 - 11.5}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMomentum(t *testing.T) {
+	got, err := MOM(stochBars.Close, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 1, 1, 3}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCMO(t *testing.T) {
+	got, err := CMO(stochBars.Close, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 100. / 3, 100. / 3, 100}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	got, err = CMO([]float64{1, 1, 1}, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, 0}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
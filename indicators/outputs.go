@@ -0,0 +1,58 @@
+package indicators
+
+import "fmt"
+
+// MultiOutputIndicator computes several named series at once, e.g. the bands of the
+// Bollinger Bands. Compute returns the primary output, Output selects any other one.
+type MultiOutputIndicator interface {
+	Indicator
+	// Outputs returns the names of all outputs.
+	Outputs() []string
+	// ComputeAll returns all outputs by name, each following the contract of Compute.
+	ComputeAll() (map[string][]float64, error)
+}
+
+var (
+	_ MultiOutputIndicator = (*BollingerBands)(nil)
+	_ MultiOutputIndicator = (*AverageDirectionalIndex)(nil)
+	_ MultiOutputIndicator = (*MovingAverageConvergenceDivergence)(nil)
+	_ MultiOutputIndicator = (*PercentagePriceOscillator)(nil)
+	_ MultiOutputIndicator = (*StochasticOscillator)(nil)
+	_ MultiOutputIndicator = (*StochasticRSI)(nil)
+	_ MultiOutputIndicator = (*DonchianChannel)(nil)
+	_ MultiOutputIndicator = (*KeltnerChannel)(nil)
+	_ MultiOutputIndicator = (*SupertrendIndicator)(nil)
+	_ MultiOutputIndicator = (*IchimokuCloud)(nil)
+	_ MultiOutputIndicator = (*AroonIndicator)(nil)
+	_ MultiOutputIndicator = (*LinearRegression)(nil)
+	_ MultiOutputIndicator = (*PivotPoints)(nil)
+	_ MultiOutputIndicator = (*FractalLevels)(nil)
+)
+
+// OutputIndicator is a single output of a multi-output indicator. It satisfies Indicator
+// and can be used in watchlist filters and rankings.
+type OutputIndicator struct {
+	Source MultiOutputIndicator
+	Name   string
+}
+
+// Output selects the named output of the indicator, e.g. Output(BB(nil, 20), BBUpper).
+func Output(ind MultiOutputIndicator, name string) *OutputIndicator {
+	return &OutputIndicator{Source: ind, Name: name}
+}
+
+func (ind *OutputIndicator) SetInput(bars *BarHistory) {
+	ind.Source.SetInput(bars)
+}
+
+func (ind *OutputIndicator) Compute() ([]float64, error) {
+	all, err := ind.Source.ComputeAll()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := all[ind.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output %q, want one of %v", ind.Name, ind.Source.Outputs())
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOutputBB(t *testing.T) {
+	bb := BB(TestBars.Close, TESTPERIOD)
+	bands, err := bb.ComputeBands()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	want := map[string][]float64{
+		BBUpper:     bands.Upper,
+		BBMean:      bands.Mean,
+		BBLower:     bands.Lower,
+		BBBandwidth: bands.BandWidth,
+		BBPercentB:  bands.PercentB,
+	}
+	for _, name := range bb.Outputs() {
+		got, err := Output(bb, name).Compute()
+		if err != nil {
+			t.Fatalf("%s: unexpected error occurred: %v ", name, err)
+		}
+		if _, err := sliceAlmostEqual(got, want[name], 1e-12, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPercentB(t *testing.T) {
+	bands, err := BB(TestBars.Close, TESTPERIOD).ComputeBands()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for i := TESTPERIOD - 1; i < TestBars.Len(); i++ {
+		price := bands.Lower[i] + bands.PercentB[i]*(bands.Upper[i]-bands.Lower[i])
+		if math.Abs(price-TestBars.Close[i]) > 1e-9 {
+			t.Fatalf("%%B at index %d: %v maps to %v, want %v", i, bands.PercentB[i], price, TestBars.Close[i])
+		}
+	}
+	if !math.IsNaN(bands.PercentB[TESTPERIOD-2]) {
+		t.Errorf("Expected NaN during warm-up, got %v", bands.PercentB[TESTPERIOD-2])
+	}
+
+	flat, err := BB([]float64{5, 5, 5, 5}, 3).ComputeBands()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(flat.PercentB, []float64{nan, nan, 0.5, 0.5}, 1e-12); err != nil {
+		t.Errorf("%%B of zero band width: %v", err)
+	}
+}
+
+func TestOutputADX(t *testing.T) {
+	adx := ADX(TestBars, TESTPERIOD)
+	plus, minus, err := adx.DiPlusMinus()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	line, err := adx.Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	for name, want := range map[string][]float64{ADXLine: line, DIPlus: plus, DIMinus: minus} {
+		got, err := Output(adx, name).Compute()
+		if err != nil {
+			t.Fatalf("%s: unexpected error occurred: %v ", name, err)
+		}
+		if _, err := sliceAlmostEqual(got, want, 1e-12, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestOutputUnknown(t *testing.T) {
+	if _, err := Output(BB(TestBars.Close, TESTPERIOD), "middle").Compute(); err == nil {
+		t.Errorf("Want error for unknown output")
+	}
+}
+
+func TestOutputSetInput(t *testing.T) {
+	upper := Output(BB(nil, TESTPERIOD), BBUpper)
+	upper.SetInput(&TestBars)
+	got, err := Latest(upper)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	bands, _ := BB(TestBars.Close, TESTPERIOD).ComputeBands()
+	if got != bands.Upper[len(bands.Upper)-1] {
+		t.Errorf("Expected %v, got %v", bands.Upper[len(bands.Upper)-1], got)
+	}
+}
@@ -0,0 +1,77 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// ParabolicStopAndReverse trails the price with a stop that accelerates towards the
+// extreme point of the trend. The acceleration factor starts at Step and grows by Step with
+// every new extreme up to Max. When the price crosses the stop the trend reverses and the
+// stop restarts at the extreme point of the previous trend. Values below the low are an up
+// trend, values above the high a down trend. The trend starts up if the second close is not
+// below the first one. The first value is NaN.
+type ParabolicStopAndReverse struct {
+	BarHistoryIndicator
+	Step float64
+	Max  float64
+}
+
+// PSAR creates the Parabolic SAR, common parameters are a step of 0.02 and a max of 0.2.
+func PSAR(bars BarHistory, step, max float64) *ParabolicStopAndReverse {
+	return &ParabolicStopAndReverse{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
+		Step:                step,
+		Max:                 max,
+	}
+}
+
+func (ind *ParabolicStopAndReverse) Compute() ([]float64, error) {
+	if ind.Step <= 0 || ind.Max < ind.Step {
+		return nil, fmt.Errorf("invalid acceleration: step %v, max %v", ind.Step, ind.Max)
+	}
+	bars := ind.Input
+	err := CheckInput(bars.Close, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars.High) != len(bars.Close) || len(bars.Low) != len(bars.Close) {
+		return nil, fmt.Errorf("parabolic SAR requires high, low and close of every bar")
+	}
+
+	res := make([]float64, len(bars.Close))
+	res[0] = math.NaN()
+	up := bars.Close[1] >= bars.Close[0]
+	af := ind.Step
+	var sar, ep float64
+	if up {
+		sar, ep = bars.Low[0], math.Max(bars.High[0], bars.High[1])
+	} else {
+		sar, ep = bars.High[0], math.Min(bars.Low[0], bars.Low[1])
+	}
+	res[1] = sar
+
+	for i := 2; i < len(res); i++ {
+		sar += af * (ep - sar)
+		if up {
+			// the stop must not enter the range of the last two bars
+			sar = math.Min(sar, math.Min(bars.Low[i-1], bars.Low[i-2]))
+			switch {
+			case bars.Low[i] < sar:
+				up, sar, ep, af = false, ep, bars.Low[i], ind.Step
+			case bars.High[i] > ep:
+				ep, af = bars.High[i], math.Min(af+ind.Step, ind.Max)
+			}
+		} else {
+			sar = math.Max(sar, math.Max(bars.High[i-1], bars.High[i-2]))
+			switch {
+			case bars.High[i] > sar:
+				up, sar, ep, af = true, ep, bars.High[i], ind.Step
+			case bars.Low[i] < ep:
+				ep, af = bars.Low[i], math.Min(af+ind.Step, ind.Max)
+			}
+		}
+		res[i] = sar
+	}
+	return res, nil
+}
@@ -0,0 +1,262 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+type pivotMethod int
+
+const (
+	// ClassicPivot is the floor trader pivot (high+low+close)/3 with three levels each side.
+	ClassicPivot pivotMethod = iota
+	// FibonacciPivot spaces the levels at 38.2%, 61.8% and 100% of the range around the
+	// classic pivot.
+	FibonacciPivot
+	// CamarillaPivot places four levels each side of the close at fractions of the range.
+	CamarillaPivot
+	// WoodiePivot weights the close twice, (high+low+2*close)/4.
+	WoodiePivot
+	// DeMarkPivot weights the high or the low twice depending on the direction of the
+	// period, it has a single level each side.
+	DeMarkPivot
+)
+
+// Names of the outputs of the pivot points.
+const (
+	PivotLevel = "pivot"
+	PivotR1    = "r1"
+	PivotR2    = "r2"
+	PivotR3    = "r3"
+	PivotR4    = "r4"
+	PivotS1    = "s1"
+	PivotS2    = "s2"
+	PivotS3    = "s3"
+	PivotS4    = "s4"
+)
+
+// PivotLevels holds the pivot and its resistance (R) and support (S) levels. Levels the
+// method does not define are NaN.
+type PivotLevels struct {
+	Pivot []float64
+	R1    []float64
+	R2    []float64
+	R3    []float64
+	R4    []float64
+	S1    []float64
+	S2    []float64
+	S3    []float64
+	S4    []float64
+}
+
+func (levels PivotLevels) outputs() map[string][]float64 {
+	return map[string][]float64{
+		PivotLevel: levels.Pivot,
+		PivotR1:    levels.R1,
+		PivotR2:    levels.R2,
+		PivotR3:    levels.R3,
+		PivotR4:    levels.R4,
+		PivotS1:    levels.S1,
+		PivotS2:    levels.S2,
+		PivotS3:    levels.S3,
+		PivotS4:    levels.S4,
+	}
+}
+
+// PivotPoints computes support and resistance levels from the open, high, low and close of
+// a period of the last Period bars, e.g. 1 for daily pivots from daily bars. The levels at
+// bar i are computed from the bars up to and including bar i and apply to the following
+// bar, so the latest levels are the ones for the next session. Use Shift to compare a bar
+// with the levels that applied to it. The first Period-1 values are NaN.
+type PivotPoints struct {
+	BarHistoryIndicator
+	Method pivotMethod
+}
+
+func Pivots(bars BarHistory, period int) *PivotPoints {
+	return &PivotPoints{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+// WithMethod sets the method of the levels.
+func (ind *PivotPoints) WithMethod(method pivotMethod) *PivotPoints {
+	ind.Method = method
+	return ind
+}
+
+// Compute returns the pivot.
+func (ind *PivotPoints) Compute() ([]float64, error) {
+	res, err := ind.ComputeLevels()
+	if err != nil {
+		return nil, err
+	}
+	return res.Pivot, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *PivotPoints) Outputs() []string {
+	return []string{PivotLevel, PivotR1, PivotR2, PivotR3, PivotR4, PivotS1, PivotS2, PivotS3, PivotS4}
+}
+
+// ComputeAll returns the levels by output name.
+func (ind *PivotPoints) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLevels()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeLevels returns all levels.
+func (ind *PivotPoints) ComputeLevels() (PivotLevels, error) {
+	bars := ind.Input
+	err := CheckInput(bars.Close, ind.Period)
+	if err != nil {
+		return PivotLevels{}, err
+	}
+	n := len(bars.Close)
+	if len(bars.High) != n || len(bars.Low) != n || (ind.Method == DeMarkPivot && len(bars.Open) != n) {
+		return PivotLevels{}, fmt.Errorf("pivot points require high, low and close of every bar, DeMark also the open")
+	}
+	if ind.Method < ClassicPivot || ind.Method > DeMarkPivot {
+		return PivotLevels{}, fmt.Errorf("unknown pivot method: %d", ind.Method)
+	}
+
+	res := PivotLevels{}
+	for _, level := range []*[]float64{&res.Pivot, &res.R1, &res.R2, &res.R3, &res.R4, &res.S1, &res.S2, &res.S3, &res.S4} {
+		*level = make([]float64, n)
+		for i := range *level {
+			(*level)[i] = math.NaN()
+		}
+	}
+	for i := ind.Period - 1; i < n; i++ {
+		high, low, c := highest(bars.High, i, ind.Period), lowest(bars.Low, i, ind.Period), bars.Close[i]
+		r := high - low
+		switch ind.Method {
+		case ClassicPivot:
+			p := (high + low + c) / 3
+			res.Pivot[i] = p
+			res.R1[i], res.S1[i] = 2*p-low, 2*p-high
+			res.R2[i], res.S2[i] = p+r, p-r
+			res.R3[i], res.S3[i] = high+2*(p-low), low-2*(high-p)
+		case FibonacciPivot:
+			p := (high + low + c) / 3
+			res.Pivot[i] = p
+			res.R1[i], res.S1[i] = p+0.382*r, p-0.382*r
+			res.R2[i], res.S2[i] = p+0.618*r, p-0.618*r
+			res.R3[i], res.S3[i] = p+r, p-r
+		case CamarillaPivot:
+			res.Pivot[i] = (high + low + c) / 3
+			res.R1[i], res.S1[i] = c+r*1.1/12, c-r*1.1/12
+			res.R2[i], res.S2[i] = c+r*1.1/6, c-r*1.1/6
+			res.R3[i], res.S3[i] = c+r*1.1/4, c-r*1.1/4
+			res.R4[i], res.S4[i] = c+r*1.1/2, c-r*1.1/2
+		case WoodiePivot:
+			p := (high + low + 2*c) / 4
+			res.Pivot[i] = p
+			res.R1[i], res.S1[i] = 2*p-low, 2*p-high
+			res.R2[i], res.S2[i] = p+r, p-r
+			res.R3[i], res.S3[i] = high+2*(p-low), low-2*(high-p)
+		case DeMarkPivot:
+			open := bars.Open[i-ind.Period+1]
+			x := high + low + 2*c
+			switch {
+			case c < open:
+				x = high + 2*low + c
+			case c > open:
+				x = 2*high + low + c
+			}
+			res.Pivot[i] = x / 4
+			res.R1[i], res.S1[i] = x/2-low, x/2-high
+		}
+	}
+	return res, nil
+}
+
+// Names of the outputs of the fractal levels.
+const (
+	FractalResistance = "resistance"
+	FractalSupport    = "support"
+)
+
+// SupportResistance holds support and resistance levels.
+type SupportResistance struct {
+	Resistance []float64
+	Support    []float64
+}
+
+// FractalLevels derives support and resistance from swing highs and lows. A swing high is a
+// high above the highs of the Period bars on either side, a swing low a low below the lows
+// of the Period bars on either side. A swing is confirmed Period bars after it occurred.
+// Resistance is the high of the last confirmed swing high and support the low of the last
+// confirmed swing low, both NaN until the first swing is confirmed.
+type FractalLevels struct {
+	BarHistoryIndicator
+}
+
+func Fractals(bars BarHistory, period int) *FractalLevels {
+	return &FractalLevels{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+// Compute returns the resistance.
+func (ind *FractalLevels) Compute() ([]float64, error) {
+	res, err := ind.ComputeLevels()
+	if err != nil {
+		return nil, err
+	}
+	return res.Resistance, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *FractalLevels) Outputs() []string {
+	return []string{FractalResistance, FractalSupport}
+}
+
+// ComputeAll returns the levels by output name.
+func (ind *FractalLevels) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLevels()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{FractalResistance: res.Resistance, FractalSupport: res.Support}, nil
+}
+
+// ComputeLevels returns resistance and support.
+func (ind *FractalLevels) ComputeLevels() (SupportResistance, error) {
+	err := CheckInput(ind.Input.High, ind.Period)
+	if err != nil {
+		return SupportResistance{}, err
+	}
+	if len(ind.Input.Low) != len(ind.Input.High) {
+		return SupportResistance{}, fmt.Errorf("fractals require high and low of every bar")
+	}
+
+	high, low := ind.Input.High, ind.Input.Low
+	n := len(high)
+	res := SupportResistance{Resistance: make([]float64, n), Support: make([]float64, n)}
+	resistance, support := math.NaN(), math.NaN()
+	for i := 0; i < n; i++ {
+		// the candidate swing has Period bars on either side
+		if j := i - ind.Period; j >= ind.Period {
+			swingHigh, swingLow := true, true
+			for k := j - ind.Period; k <= i; k++ {
+				if k == j {
+					continue
+				}
+				swingHigh = swingHigh && high[j] > high[k]
+				swingLow = swingLow && low[j] < low[k]
+			}
+			if swingHigh {
+				resistance = high[j]
+			}
+			if swingLow {
+				support = low[j]
+			}
+		}
+		res.Resistance[i], res.Support[i] = resistance, support
+	}
+	return res, nil
+}
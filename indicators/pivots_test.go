@@ -0,0 +1,87 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPivots(t *testing.T) {
+	bars := BarHistory{Open: []float64{9}, High: []float64{12}, Low: []float64{8}, Close: []float64{11}}
+	p := 31. / 3
+	tests := map[pivotMethod][9]float64{
+		// pivot, r1, r2, r3, r4, s1, s2, s3, s4
+		ClassicPivot:   {p, 2*p - 8, p + 4, 12 + 2*(p-8), nan, 2*p - 12, p - 4, 8 - 2*(12-p), nan},
+		FibonacciPivot: {p, p + 0.382*4, p + 0.618*4, p + 4, nan, p - 0.382*4, p - 0.618*4, p - 4, nan},
+		CamarillaPivot: {p, 11 + 4.4/12, 11 + 4.4/6, 11 + 4.4/4, 11 + 2.2, 11 - 4.4/12, 11 - 4.4/6, 11 - 4.4/4, 11 - 2.2},
+		WoodiePivot:    {10.5, 13, 14.5, 17, nan, 9, 6.5, 5, nan},
+		DeMarkPivot:    {10.75, 13.5, nan, nan, nan, 9.5, nan, nan, nan},
+	}
+	for method, want := range tests {
+		// a second bar is needed by CheckInput, its levels are checked
+		two := BarHistory{
+			Open:  append([]float64{1}, bars.Open...),
+			High:  append([]float64{1}, bars.High...),
+			Low:   append([]float64{1}, bars.Low...),
+			Close: append([]float64{1}, bars.Close...),
+		}
+		got, err := Pivots(two, 1).WithMethod(method).ComputeLevels()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		levels := []float64{got.Pivot[1], got.R1[1], got.R2[1], got.R3[1], got.R4[1], got.S1[1], got.S2[1], got.S3[1], got.S4[1]}
+		if _, err := sliceAlmostEqual(levels, want[:], 1e-9, fmt.Sprintf("method %d: ", method)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPivotsPeriod(t *testing.T) {
+	// the period of the last two bars spans from the low of 8 to the high of 12
+	bars := BarHistory{
+		Open:  []float64{9, 9, 10},
+		High:  []float64{10, 12, 11},
+		Low:   []float64{8, 9, 8},
+		Close: []float64{9, 10, 11},
+	}
+	got, err := Pivots(bars, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, 30. / 3, 31. / 3}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	bars.Open = nil
+	if _, err := Pivots(bars, 2).WithMethod(DeMarkPivot).Compute(); err == nil {
+		t.Errorf("Want error without open")
+	}
+	if _, err := Pivots(stochBars, 2).WithMethod(pivotMethod(99)).Compute(); err == nil {
+		t.Errorf("Want error for unknown method")
+	}
+}
+
+func TestFractals(t *testing.T) {
+	bars := BarHistory{
+		High:  []float64{1, 2, 5, 3, 2, 4, 6, 5, 4, 3},
+		Low:   []float64{0, 1, 3, 2, 1, 2, 5, 4, 3, 2},
+		Close: []float64{1, 2, 4, 3, 2, 3, 5, 5, 4, 3},
+	}
+	got, err := Fractals(bars, 2).ComputeLevels()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// the swing high at bar 2 is confirmed at bar 4, the one at bar 6 at bar 8
+	if _, err := sliceAlmostEqual(got.Resistance, []float64{nan, nan, nan, nan, 5, 5, 5, 5, 6, 6}, 1e-9, "resistance: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.Support, []float64{nan, nan, nan, nan, nan, nan, 1, 1, 1, 1}, 1e-9, "support: "); err != nil {
+		t.Error(err)
+	}
+
+	support := Output(Fractals(BarHistory{}, 2), FractalSupport)
+	support.SetInput(&bars)
+	if v, err := Latest(support); err != nil || math.Abs(v-1) > 1e-9 {
+		t.Errorf("Expected latest support 1, got %v, %v", v, err)
+	}
+}
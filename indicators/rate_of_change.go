@@ -1,5 +1,7 @@
 package indicators
 
+import "math"
+
 func ROC(input []float64, period int) *RateOfChange {
 	return &RateOfChange{
 		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
@@ -8,14 +10,47 @@ func ROC(input []float64, period int) *RateOfChange {
 
 type RateOfChange struct {
 	TimeSeriesIndicator
+
+	window ringBuffer
 }
 
-func (ind *RateOfChange) Compute() []float64 {
+func (ind *RateOfChange) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
 	roc := make([]float64, len(ind.Input))
+	for i := 0; i < ind.Period; i++ {
+		roc[i] = math.NaN()
+	}
 
 	for i := ind.Period; i < len(ind.Input); i++ {
 		roc[i] = ((ind.Input[i] - ind.Input[i-ind.Period]) / ind.Input[i-ind.Period]) * 100
 	}
 
-	return roc
+	return roc, nil
+}
+
+// Update adds the close of the bar and returns the current rate of change.
+func (ind *RateOfChange) Update(bar Bar) float64 {
+	return ind.UpdateValue(bar.Close)
+}
+
+// UpdateValue adds the value and returns the current rate of change.
+func (ind *RateOfChange) UpdateValue(value float64) float64 {
+	if ind.window.values == nil {
+		ind.window = newRingBuffer(ind.Period + 1)
+	}
+	ind.window.push(value)
+	if !ind.window.full() {
+		return math.NaN()
+	}
+	previous := ind.window.at(ind.Period)
+	return ((value - previous) / previous) * 100
+}
+
+// Reset clears the state of Update.
+func (ind *RateOfChange) Reset() {
+	ind.window = ringBuffer{}
 }
@@ -0,0 +1,94 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// benchmarkClose returns the close of the benchmark aligned with the bars. If both carry
+// timestamps the benchmark close of the same timestamp is used and missing ones are NaN,
+// otherwise both must have the same number of bars.
+func benchmarkClose(bars, benchmark *BarHistory) ([]float64, error) {
+	if benchmark.Len() == 0 {
+		return nil, fmt.Errorf("no benchmark bars")
+	}
+	if !bars.HasTime() || !benchmark.HasTime() {
+		if benchmark.Len() != bars.Len() {
+			return nil, fmt.Errorf("benchmark without timestamps must be aligned with the bars: %d != %d bars", benchmark.Len(), bars.Len())
+		}
+		return benchmark.Close, nil
+	}
+	res := make([]float64, bars.Len())
+	for i, t := range bars.Time {
+		res[i] = math.NaN()
+		if j := benchmark.IndexOf(t); j >= 0 {
+			res[i] = benchmark.Close[j]
+		}
+	}
+	return res, nil
+}
+
+// RelativeStrengthRatio is the close divided by the close of a benchmark such as an index.
+// A rising ratio means the asset outperforms the benchmark. The benchmark is matched by
+// timestamp, see benchmarkClose, and values without a benchmark bar are NaN. It has no
+// warm-up period.
+type RelativeStrengthRatio struct {
+	BarHistoryIndicator
+	Benchmark BarHistory
+}
+
+func RS(bars, benchmark BarHistory) *RelativeStrengthRatio {
+	return &RelativeStrengthRatio{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
+		Benchmark:           benchmark,
+	}
+}
+
+func (ind *RelativeStrengthRatio) Compute() ([]float64, error) {
+	if ind.Input.Len() == 0 {
+		return nil, fmt.Errorf("no bars")
+	}
+	benchmark, err := benchmarkClose(&ind.Input, &ind.Benchmark)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, ind.Input.Len())
+	for i := range res {
+		res[i] = ind.Input.Close[i] / benchmark[i]
+	}
+	return res, nil
+}
+
+// MansfieldRelativeStrength is the relative strength ratio in percent above or below its
+// simple moving average over Period bars, positive values mean the asset outperforms the
+// benchmark more than usual. The first Period-1 values are NaN, as are the values whose
+// window lacks a benchmark bar.
+type MansfieldRelativeStrength struct {
+	BarHistoryIndicator
+	Benchmark BarHistory
+}
+
+// MansfieldRS creates the Mansfield relative strength, common periods are 52 for weekly and
+// 200 for daily bars.
+func MansfieldRS(bars, benchmark BarHistory, period int) *MansfieldRelativeStrength {
+	return &MansfieldRelativeStrength{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		Benchmark:           benchmark,
+	}
+}
+
+func (ind *MansfieldRelativeStrength) Compute() ([]float64, error) {
+	ratio, err := RS(ind.Input, ind.Benchmark).Compute()
+	if err != nil {
+		return nil, err
+	}
+	mean, err := MA(ratio, ind.Period).Compute()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ratio))
+	for i := range res {
+		res[i] = (ratio[i]/mean[i] - 1) * 100
+	}
+	return res, nil
+}
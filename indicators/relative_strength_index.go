@@ -1,5 +1,7 @@
 package indicators
 
+import "math"
+
 func RSI(input []float64, period int) *RelativeStrengthIndex {
 	return &RelativeStrengthIndex{
 		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
@@ -8,12 +10,17 @@ func RSI(input []float64, period int) *RelativeStrengthIndex {
 
 type RelativeStrengthIndex struct {
 	TimeSeriesIndicator
-}
 
-func (ind *RelativeStrengthIndex) Compute() []float64 {
+	count    int
+	previous float64
+	avgGain  float64
+	avgLoss  float64
+}
 
-	if len(ind.Input) < ind.Period {
-		return nil
+func (ind *RelativeStrengthIndex) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
 	}
 
 	rsi := make([]float64, len(ind.Input))
@@ -38,9 +45,11 @@ func (ind *RelativeStrengthIndex) Compute() []float64 {
 		rsi[i] = 100.0 - (100.0 / (1.0 + sumGains/sumLosses))
 	}
 	// first value is just to start the averaging
-	rsi[ind.Period-1] = 0.0
+	for i := 0; i < ind.Period; i++ {
+		rsi[i] = math.NaN()
+	}
 	ind.Values = rsi
-	return rsi
+	return rsi, nil
 }
 
 func (ind *RelativeStrengthIndex) calculateGainLoss(currentPrice, previousPrice float64) (float64, float64) {
@@ -57,3 +66,42 @@ func (ind *RelativeStrengthIndex) calculateGainLoss(currentPrice, previousPrice
 
 	return gain, loss
 }
+
+// Update adds the close of the bar and returns the current RSI.
+func (ind *RelativeStrengthIndex) Update(bar Bar) float64 {
+	return ind.UpdateValue(bar.Close)
+}
+
+// UpdateValue adds the value and returns the current RSI.
+func (ind *RelativeStrengthIndex) UpdateValue(value float64) float64 {
+	ind.count++
+	defer func() { ind.previous = value }()
+
+	if ind.count > 1 {
+		gain, loss := ind.calculateGainLoss(value, ind.previous)
+		if ind.count <= ind.Period {
+			ind.avgGain += gain
+			ind.avgLoss += loss
+		} else {
+			ind.avgGain = (ind.avgGain*float64(ind.Period-1) + gain) / float64(ind.Period)
+			ind.avgLoss = (ind.avgLoss*float64(ind.Period-1) + loss) / float64(ind.Period)
+		}
+	}
+	if ind.count == ind.Period {
+		ind.avgGain /= float64(ind.Period)
+		ind.avgLoss /= float64(ind.Period)
+	}
+	// the first value is just to start the averaging
+	if ind.count <= ind.Period {
+		return math.NaN()
+	}
+	return 100.0 - (100.0 / (1.0 + ind.avgGain/ind.avgLoss))
+}
+
+// Reset clears the state of Update.
+func (ind *RelativeStrengthIndex) Reset() {
+	ind.count = 0
+	ind.previous = 0
+	ind.avgGain = 0
+	ind.avgLoss = 0
+}
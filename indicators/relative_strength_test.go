@@ -0,0 +1,88 @@
+package indicators
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRS(t *testing.T) {
+	bars := dated(1, 10, 12, 12, 15)
+	// the benchmark lacks the second day
+	benchmark := dated(1, 100, 0, 120, 100)
+	benchmark.Time = append(benchmark.Time[:1], benchmark.Time[2:]...)
+	benchmark.Close = append(benchmark.Close[:1], benchmark.Close[2:]...)
+
+	got, err := RS(*bars, *benchmark).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{0.1, nan, 0.1, 0.15}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	bars.Time = nil
+	if _, err := RS(*bars, *benchmark).Compute(); err == nil {
+		t.Errorf("Want error for unaligned benchmark without timestamps")
+	}
+}
+
+func TestMansfieldRS(t *testing.T) {
+	bars := dated(1, 10, 11, 12, 13, 15)
+	benchmark := dated(1, 100, 100, 100, 100, 100)
+	got, err := MansfieldRS(*bars, *benchmark, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, nan, (0.12/0.11 - 1) * 100, (0.13/0.12 - 1) * 100, (0.15/(0.40/3) - 1) * 100}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	// the indicator is set up once and computed for every symbol of a watchlist
+	ind := MansfieldRS(BarHistory{}, *benchmark, 3)
+	ind.SetInput(bars)
+	if v, err := Latest(ind); err != nil || v <= 0 {
+		t.Errorf("Expected outperformance, got %v, %v", v, err)
+	}
+}
+
+func TestCrossSectionalRank(t *testing.T) {
+	histories := map[string]*BarHistory{
+		"A": dated(1, 1, 2, 3),
+		"B": dated(1, 2, 2, 1),
+		"C": dated(1, 3, 2, 2),
+		"D": dated(2, 5),
+	}
+	got, err := CrossSectionalRank(Last(BarHistory{}, Close), histories)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for symbol, want := range map[string][]float64{
+		"A": {0, 100. / 3, 100},
+		"B": {50, 100. / 3, 0},
+		"C": {100, 100. / 3, 50},
+		"D": {100},
+	} {
+		if _, err := sliceAlmostEqual(got[symbol], want, 1e-9, symbol+": "); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// warm-up values and failing indicators are not ranked
+	got, err = CrossSectionalRank(ROC(nil, 1), histories)
+	var failed RankError
+	if !errors.As(err, &failed) || len(failed) != 1 || failed["D"] == nil {
+		t.Fatalf("Expected failure of D, got %v", err)
+	}
+	if _, err := sliceAlmostEqual(got["A"], []float64{nan, 100, 100}, 1e-9); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got["D"], []float64{nan}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	histories["E"] = &BarHistory{Close: []float64{1}}
+	if _, err := CrossSectionalRank(Last(BarHistory{}, Close), histories); err == nil {
+		t.Errorf("Want error without timestamps")
+	}
+}
@@ -1,7 +1,11 @@
 package indicators
 
+import "math"
+
 type RelativeStrengthLevy struct {
 	TimeSeriesIndicator
+
+	window ringBuffer
 }
 
 func RSL(input []float64, period int) *RelativeStrengthLevy {
@@ -16,8 +20,33 @@ func (ind *RelativeStrengthLevy) Compute() ([]float64, error) {
 		return nil, err
 	}
 	res := make([]float64, len(ind.Input))
+	for i := 0; i < ind.Period; i++ {
+		res[i] = math.NaN()
+	}
 	for i := ind.Period; i < len(ind.Input); i++ {
 		res[i] = ind.Input[i] / Mean(ind.Input[i-ind.Period:i+1])
 	}
 	return res, nil
 }
+
+// Update adds the close of the bar and returns the current RSL.
+func (ind *RelativeStrengthLevy) Update(bar Bar) float64 {
+	return ind.UpdateValue(bar.Close)
+}
+
+// UpdateValue adds the value and returns the current RSL.
+func (ind *RelativeStrengthLevy) UpdateValue(value float64) float64 {
+	if ind.window.values == nil {
+		ind.window = newRingBuffer(ind.Period + 1)
+	}
+	ind.window.push(value)
+	if !ind.window.full() {
+		return math.NaN()
+	}
+	return value / Mean(ind.window.ordered())
+}
+
+// Reset clears the state of Update.
+func (ind *RelativeStrengthLevy) Reset() {
+	ind.window = ringBuffer{}
+}
@@ -0,0 +1,41 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// ShiftedIndicator lags another indicator by a number of bars, e.g. to compare the close
+// to the 20 day high of the previous bars.
+type ShiftedIndicator struct {
+	Source Indicator
+	Bars   int
+}
+
+// Shift lags the indicator by n bars, the value at bar i is the one of the indicator at
+// bar i-n. The first n values are NaN.
+func Shift(ind Indicator, n int) *ShiftedIndicator {
+	return &ShiftedIndicator{Source: ind, Bars: n}
+}
+
+func (ind *ShiftedIndicator) SetInput(bars *BarHistory) {
+	ind.Source.SetInput(bars)
+}
+
+func (ind *ShiftedIndicator) Compute() ([]float64, error) {
+	if ind.Bars < 0 {
+		return nil, fmt.Errorf("invalid shift: %d", ind.Bars)
+	}
+	values, err := ind.Source.Compute()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(values))
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= ind.Bars {
+			res[i] = values[i-ind.Bars]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package indicators
+
+import "math"
+
+// RollingZScore is the distance of the input from its mean over the last Period values in
+// standard deviations, using the population standard deviation like StdDev. It is 0 if the
+// values did not change. The first Period-1 values are NaN.
+type RollingZScore struct {
+	TimeSeriesIndicator
+}
+
+func ZScore(input []float64, period int) *RollingZScore {
+	return &RollingZScore{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *RollingZScore) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		window := ind.Input[i-ind.Period+1 : i+1]
+		if sd := StdDev(window); sd != 0 {
+			res[i] = (ind.Input[i] - Mean(window)) / sd
+		}
+	}
+	return res, nil
+}
+
+// PercentileRank is the percentage of the previous Period values that are below the
+// current one, from 0 for a new low to 100 for a new high. Equal values count half. The
+// first Period values are NaN.
+type PercentileRank struct {
+	TimeSeriesIndicator
+}
+
+func PercentRank(input []float64, period int) *PercentileRank {
+	return &PercentileRank{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *PercentileRank) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(ind.Input))
+	for i := range res {
+		if i < ind.Period {
+			res[i] = math.NaN()
+			continue
+		}
+		below := 0.0
+		for j := i - ind.Period; j < i; j++ {
+			switch {
+			case ind.Input[j] < ind.Input[i]:
+				below++
+			case ind.Input[j] == ind.Input[i]:
+				below += 0.5
+			}
+		}
+		res[i] = 100 * below / float64(ind.Period)
+	}
+	return res, nil
+}
@@ -0,0 +1,167 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZScore(t *testing.T) {
+	got, err := ZScore([]float64{1, 2, 3, 4, 8}, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, nan, 1 / math.Sqrt(2./3), 1 / math.Sqrt(2./3), 3 / math.Sqrt(14./3)}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPercentRank(t *testing.T) {
+	got, err := PercentRank([]float64{1, 2, 3, 2, 5}, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, 50, 100}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLinReg(t *testing.T) {
+	got, err := LinReg([]float64{1, 3, 5, 7, 9}, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for name, pair := range map[string][2][]float64{
+		"slope":     {got.Slope, {nan, nan, 2, 2, 2}},
+		"intercept": {got.Intercept, {nan, nan, 1, 3, 5}},
+		"r2":        {got.R2, {nan, nan, 1, 1, 1}},
+		"stderr":    {got.StdErr, {nan, nan, 0, 0, 0}},
+		"adjusted":  {got.AdjustedSlope, {nan, nan, 2, 2, 2}},
+	} {
+		if _, err := sliceAlmostEqual(pair[0], pair[1], 1e-9, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+
+	got, err = LinReg([]float64{1, 2, 1, 1, 1}, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if got.Slope[2] != 0 || got.R2[2] != 0 || math.Abs(got.Intercept[2]-4./3) > 1e-9 || math.Abs(got.StdErr[2]-math.Sqrt(2./3)) > 1e-9 {
+		t.Errorf("Unexpected fit of a flat window: %v, %v, %v, %v", got.Slope[2], got.Intercept[2], got.R2[2], got.StdErr[2])
+	}
+	// R² of a constant window
+	if got.R2[4] != 0 {
+		t.Errorf("Expected R² of 0, got %v", got.R2[4])
+	}
+
+	slope, err := LinReg([]float64{1, math.E, math.E * math.E, math.E * math.E * math.E}, 3).WithLog().Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(slope, []float64{nan, nan, 1, 1}, 1e-9); err != nil {
+		t.Error(err)
+	}
+	if _, err := LinReg([]float64{1, 0, 1}, 2).WithLog().Compute(); err == nil {
+		t.Errorf("Want error for the logarithm of 0")
+	}
+}
+
+func TestCorrelationBeta(t *testing.T) {
+	benchmark := dated(1, 100, 101, 99, 102, 100, 103)
+	double := []float64{50}
+	inverse := []float64{50}
+	for i := 1; i < benchmark.Len(); i++ {
+		r := benchmark.Close[i]/benchmark.Close[i-1] - 1
+		double = append(double, double[i-1]*(1+2*r))
+		inverse = append(inverse, inverse[i-1]*(1-r))
+	}
+
+	for _, c := range []struct {
+		input       *BarHistory
+		correlation float64
+		beta        float64
+	}{{dated(1, double...), 1, 2}, {dated(1, inverse...), -1, -1}} {
+		corr, err := Correlation(*c.input, *benchmark, 3).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		want := []float64{nan, nan, nan, c.correlation, c.correlation, c.correlation}
+		if _, err := sliceAlmostEqual(corr, want, 1e-9, "correlation: "); err != nil {
+			t.Error(err)
+		}
+		beta, err := Beta(*c.input, *benchmark, 3).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		want = []float64{nan, nan, nan, c.beta, c.beta, c.beta}
+		if _, err := sliceAlmostEqual(beta, want, 1e-9, "beta: "); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// the benchmark is matched by timestamp if the asset lacks a bar
+	input := dated(1, double...)
+	gap := BarHistory{Time: append(input.Time[:2:2], input.Time[3:]...), Close: append(input.Close[:2:2], input.Close[3:]...)}
+	matched := BarHistory{Time: gap.Time, Close: append(benchmark.Close[:2:2], benchmark.Close[3:]...)}
+	got, err := Beta(gap, *benchmark, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want, _ := Beta(gap, matched, 2).Compute()
+	if _, err := sliceAlmostEqual(got, want, 1e-12, "gap in input: "); err != nil {
+		t.Error(err)
+	}
+	// windows lacking a benchmark bar are NaN
+	got, err = Beta(*input, matched, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, nan, nan, 2}, 1e-9, "gap in benchmark: "); err != nil {
+		t.Error(err)
+	}
+
+	undated := BarHistory{Close: double}
+	if _, err := Beta(undated, BarHistory{Close: benchmark.Close[1:]}, 3).Compute(); err == nil {
+		t.Errorf("Want error for unaligned benchmark")
+	}
+}
+
+func TestHistoricalVolatility(t *testing.T) {
+	got, err := HV(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	var logReturns []float64
+	for i := 1; i <= TESTPERIOD; i++ {
+		logReturns = append(logReturns, math.Log(TestBars.Close[i]/TestBars.Close[i-1]))
+	}
+	if !math.IsNaN(got[TESTPERIOD-1]) || math.Abs(got[TESTPERIOD]-StdDev(logReturns)*math.Sqrt(TradingDays)*100) > 1e-9 {
+		t.Errorf("Unexpected close-to-close volatility: %v, %v", got[TESTPERIOD-1], got[TESTPERIOD])
+	}
+
+	// every bar spans 10% in log terms and closes at the open
+	bars := BarHistory{
+		Open:  []float64{100, 101, 102, 103},
+		Low:   []float64{100, 101, 102, 103},
+		Close: []float64{100, 101, 102, 103},
+	}
+	for _, low := range bars.Low {
+		bars.High = append(bars.High, low*math.Exp(0.1))
+	}
+	for method, variance := range map[volatilityMethod]float64{Parkinson: 0.01 / (4 * math.Ln2), GarmanKlass: 0.5 * 0.01} {
+		got, err := HV(bars, 2).WithMethod(method).WithBarsPerYear(52).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		want := math.Sqrt(variance*52) * 100
+		if _, err := sliceAlmostEqual(got, []float64{nan, want, want, want}, 1e-9); err != nil {
+			t.Errorf("method %d: %v", method, err)
+		}
+	}
+
+	bars.Open = nil
+	if _, err := HV(bars, 2).WithMethod(GarmanKlass).Compute(); err == nil {
+		t.Errorf("Want error without open")
+	}
+}
@@ -0,0 +1,201 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the stochastic oscillators.
+const (
+	StochK = "%K"
+	StochD = "%D"
+)
+
+// StochasticLines holds the outputs of the stochastic oscillators.
+type StochasticLines struct {
+	K []float64
+	D []float64
+}
+
+func (lines StochasticLines) outputs() map[string][]float64 {
+	return map[string][]float64{StochK: lines.K, StochD: lines.D}
+}
+
+// StochasticOscillator locates the close within the range of the last KPeriod bars on a
+// scale from 0 at the lowest low to 100 at the highest high. %K is that location smoothed
+// over Smoothing bars and %D a moving average of %K over DPeriod bars. If the range is
+// empty the location is 50. %K is NaN for the first KPeriod+Smoothing-2 values and %D for
+// the first KPeriod+Smoothing+DPeriod-3 values.
+type StochasticOscillator struct {
+	BarHistoryIndicator
+	KPeriod   int
+	Smoothing int
+	DPeriod   int
+	// Type of the moving averages, defaults to SMA.
+	Type maType
+}
+
+// Stochastic creates the full stochastic oscillator.
+func Stochastic(bars BarHistory, kPeriod, smoothing, dPeriod int) *StochasticOscillator {
+	return &StochasticOscillator{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, kPeriod),
+		KPeriod:             kPeriod,
+		Smoothing:           smoothing,
+		DPeriod:             dPeriod,
+	}
+}
+
+// FastStochastic creates the fast stochastic oscillator, whose %K is not smoothed.
+func FastStochastic(bars BarHistory, kPeriod, dPeriod int) *StochasticOscillator {
+	return Stochastic(bars, kPeriod, 1, dPeriod)
+}
+
+// SlowStochastic creates the slow stochastic oscillator, whose %K is the %D of the fast one.
+func SlowStochastic(bars BarHistory, kPeriod, dPeriod int) *StochasticOscillator {
+	return Stochastic(bars, kPeriod, 3, dPeriod)
+}
+
+// WithType sets the type of the moving averages.
+func (ind *StochasticOscillator) WithType(matype maType) *StochasticOscillator {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns %K.
+func (ind *StochasticOscillator) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.K, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *StochasticOscillator) Outputs() []string {
+	return []string{StochK, StochD}
+}
+
+// ComputeAll returns %K and %D by output name.
+func (ind *StochasticOscillator) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeLines returns %K and %D.
+func (ind *StochasticOscillator) ComputeLines() (StochasticLines, error) {
+	if ind.KPeriod <= 0 {
+		return StochasticLines{}, fmt.Errorf("invalid period: %d", ind.KPeriod)
+	}
+	if len(ind.Input.High) != len(ind.Input.Close) || len(ind.Input.Low) != len(ind.Input.Close) {
+		return StochasticLines{}, fmt.Errorf("stochastic requires high, low and close of every bar")
+	}
+	raw := stochastic(ind.Input.Close, ind.Input.High, ind.Input.Low, 0, ind.KPeriod)
+	return stochasticLines(raw, volumeOf(&ind.Input), ind.KPeriod-1, ind.Smoothing, ind.DPeriod, ind.Type)
+}
+
+// stochastic returns the location of value within the range of the last period values of
+// high and low. Values before start are ignored.
+func stochastic(value, high, low []float64, start, period int) []float64 {
+	res := make([]float64, len(value))
+	for i := range res {
+		res[i] = math.NaN()
+		if i < start+period-1 {
+			continue
+		}
+		hh, ll := highest(high, i, period), lowest(low, i, period)
+		if hh == ll {
+			res[i] = 50
+			continue
+		}
+		res[i] = 100 * (value[i] - ll) / (hh - ll)
+	}
+	return res
+}
+
+// stochasticLines smooths the raw stochastic, whose first value is at index start.
+func stochasticLines(raw, volume []float64, start, smoothing, dPeriod int, matype maType) (StochasticLines, error) {
+	if start < 0 || smoothing <= 0 || dPeriod <= 0 {
+		return StochasticLines{}, fmt.Errorf("invalid periods: %%K %d, smoothing %d, %%D %d", start+1, smoothing, dPeriod)
+	}
+	err := CheckInput(raw, start+smoothing+dPeriod-1)
+	if err != nil {
+		return StochasticLines{}, err
+	}
+	k, err := smooth(raw, volume, start, smoothing, matype)
+	if err != nil {
+		return StochasticLines{}, err
+	}
+	d, err := smooth(k, volume, start+smoothing-1, dPeriod, matype)
+	if err != nil {
+		return StochasticLines{}, err
+	}
+	return StochasticLines{K: k, D: d}, nil
+}
+
+// StochasticRSI is the stochastic oscillator applied to the RSI of the input, i.e. it
+// locates the RSI within its range of the last StochPeriod values. %K is NaN for the first
+// RSIPeriod+StochPeriod+Smoothing-2 values.
+type StochasticRSI struct {
+	TimeSeriesIndicator
+	RSIPeriod   int
+	StochPeriod int
+	Smoothing   int
+	DPeriod     int
+	// Type of the moving averages, defaults to SMA.
+	Type maType
+}
+
+func StochRSI(input []float64, rsiPeriod, stochPeriod, smoothing, dPeriod int) *StochasticRSI {
+	return &StochasticRSI{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, rsiPeriod),
+		RSIPeriod:           rsiPeriod,
+		StochPeriod:         stochPeriod,
+		Smoothing:           smoothing,
+		DPeriod:             dPeriod,
+	}
+}
+
+// WithType sets the type of the moving averages.
+func (ind *StochasticRSI) WithType(matype maType) *StochasticRSI {
+	ind.Type = matype
+	return ind
+}
+
+// Compute returns %K.
+func (ind *StochasticRSI) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.K, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *StochasticRSI) Outputs() []string {
+	return []string{StochK, StochD}
+}
+
+// ComputeAll returns %K and %D by output name.
+func (ind *StochasticRSI) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.outputs(), nil
+}
+
+// ComputeLines returns %K and %D.
+func (ind *StochasticRSI) ComputeLines() (StochasticLines, error) {
+	if ind.StochPeriod <= 0 {
+		return StochasticLines{}, fmt.Errorf("invalid period: %d", ind.StochPeriod)
+	}
+	rsi, err := RSI(ind.Input, ind.RSIPeriod).Compute()
+	if err != nil {
+		return StochasticLines{}, err
+	}
+	raw := stochastic(rsi, rsi, rsi, ind.RSIPeriod, ind.StochPeriod)
+	return stochasticLines(raw, ind.Volume, ind.RSIPeriod+ind.StochPeriod-1, ind.Smoothing, ind.DPeriod, ind.Type)
+}
@@ -0,0 +1,125 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+var stochBars = BarHistory{
+	High:  []float64{10, 12, 11, 13, 14},
+	Low:   []float64{8, 9, 9, 10, 12},
+	Close: []float64{9, 11, 10, 12, 13},
+	Open:  []float64{9, 9, 11, 10, 12},
+}
+
+func TestFastStochastic(t *testing.T) {
+	got, err := FastStochastic(stochBars, 3, 2).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got.K, []float64{nan, nan, 50, 75, 80}, 1e-9, "%K: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.D, []float64{nan, nan, nan, 62.5, 77.5}, 1e-9, "%D: "); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlowStochastic(t *testing.T) {
+	fast, err := FastStochastic(TestBars, 14, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	slow, err := SlowStochastic(TestBars, 14, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// the slow %K is the fast %D
+	if _, err := sliceAlmostEqual(slow.K, fast.D, 1e-9); err != nil {
+		t.Error(err)
+	}
+	for i := 14 + 3 + 3 - 3; i < TestBars.Len(); i++ {
+		if slow.D[i] < 0 || slow.D[i] > 100 {
+			t.Errorf("%%D out of range at index %d: %v", i, slow.D[i])
+		}
+	}
+	if !math.IsNaN(slow.D[14+3+3-4]) {
+		t.Errorf("Expected NaN during warm-up, got %v", slow.D[14+3+3-4])
+	}
+}
+
+func TestStochasticInvalid(t *testing.T) {
+	for _, periods := range [][3]int{{0, 1, 3}, {3, 0, 3}, {3, 1, 0}, {3, 2, 2}} {
+		if _, err := Stochastic(stochBars, periods[0], periods[1], periods[2]).Compute(); err == nil {
+			t.Errorf("Want error for periods %v", periods)
+		}
+	}
+}
+
+func TestStochasticFlatRange(t *testing.T) {
+	flat := BarHistory{High: []float64{5, 5, 5, 5}, Low: []float64{5, 5, 5, 5}, Close: []float64{5, 5, 5, 5}}
+	got, err := FastStochastic(flat, 2, 1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if got[3] != 50 {
+		t.Errorf("Expected 50 for an empty range, got %v", got[3])
+	}
+}
+
+func TestWilliamsR(t *testing.T) {
+	got, err := WilliamsR(stochBars, 3).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, -50, -25, -20}, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	// Williams %R is the fast %K shifted by 100
+	k, err := FastStochastic(TestBars, TESTPERIOD, 1).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got, err = WilliamsR(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for i := range k {
+		k[i] -= 100
+	}
+	if _, err := sliceAlmostEqual(got, k, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStochRSI(t *testing.T) {
+	rsiPeriod, stochPeriod := 5, 5
+	got, err := StochRSI(TestBars.Close, rsiPeriod, stochPeriod, 1, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	// the fast stochastic of bars made of the RSI
+	rsi, err := RSI(TestBars.Close, rsiPeriod).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	rsiBars := BarHistory{High: rsi[rsiPeriod:], Low: rsi[rsiPeriod:], Close: rsi[rsiPeriod:]}
+	want, err := FastStochastic(rsiBars, stochPeriod, 3).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	for i := 0; i < rsiPeriod; i++ {
+		if !math.IsNaN(got.K[i]) || !math.IsNaN(got.D[i]) {
+			t.Fatalf("Expected NaN during warm-up at index %d", i)
+		}
+	}
+	if _, err := sliceAlmostEqual(got.K[rsiPeriod:], want.K, 1e-9, "%K: "); err != nil {
+		t.Error(err)
+	}
+	if _, err := sliceAlmostEqual(got.D[rsiPeriod:], want.D, 1e-9, "%D: "); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,71 @@
+package indicators
+
+import "time"
+
+// Bar is a single bar fed to a streaming indicator.
+type Bar struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume int64
+}
+
+// Bar returns the bar at index i. Columns that are not populated are left at zero.
+func (bars *BarHistory) Bar(i int) Bar {
+	bar := Bar{Open: bars.Open[i], High: bars.High[i], Low: bars.Low[i], Close: bars.Close[i]}
+	if i < len(bars.Time) {
+		bar.Time = bars.Time[i]
+	}
+	if i < len(bars.Volume) {
+		bar.Volume = bars.Volume[i]
+	}
+	return bar
+}
+
+// StreamingIndicator keeps its state between calls and computes the value for one new bar
+// at a time. Feeding the bars of a history one by one yields the same values as the batch
+// computation over the whole history.
+type StreamingIndicator interface {
+	Update(bar Bar) float64
+	Reset()
+}
+
+// ringBuffer holds the last values of a series.
+type ringBuffer struct {
+	values []float64
+	next   int
+	count  int
+}
+
+func newRingBuffer(size int) ringBuffer {
+	return ringBuffer{values: make([]float64, size)}
+}
+
+func (r *ringBuffer) push(v float64) {
+	r.values[r.next] = v
+	r.next = (r.next + 1) % len(r.values)
+	r.count++
+}
+
+// full reports whether the buffer has been filled completely.
+func (r *ringBuffer) full() bool {
+	return r.count >= len(r.values)
+}
+
+// at returns the value pushed j calls ago, at(0) is the most recent value.
+func (r *ringBuffer) at(j int) float64 {
+	n := len(r.values)
+	return r.values[((r.next-1-j)%n+n)%n]
+}
+
+// ordered returns the buffered values from the oldest to the most recent.
+func (r *ringBuffer) ordered() []float64 {
+	n := len(r.values)
+	res := make([]float64, n)
+	for j := 0; j < n; j++ {
+		res[n-1-j] = r.at(j)
+	}
+	return res
+}
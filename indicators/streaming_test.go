@@ -0,0 +1,165 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+)
+
+// stream feeds the test bars one by one into the indicator.
+func stream(ind StreamingIndicator) []float64 {
+	res := make([]float64, TestBars.Len())
+	for i := range res {
+		res[i] = ind.Update(TestBars.Bar(i))
+	}
+	return res
+}
+
+// streamTwice checks that Reset clears the state and returns the values of the second run.
+func streamTwice(t *testing.T, ind StreamingIndicator) []float64 {
+	first := stream(ind)
+	ind.Reset()
+	second := stream(ind)
+	if _, err := sliceAlmostEqual(first, second, 1e-12); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	return second
+}
+
+func TestStreamingMA(t *testing.T) {
+	for _, matype := range []maType{SMA, EMA, LWMA, WILDER, DEMA, TEMA, HMA, KAMA, ALMA, T3, VWMA} {
+		for _, period := range []int{1, TESTPERIOD, 20} {
+			want, err := MA(TestBars.Close, period).WithType(matype).WithVolume(volumeOf(&TestBars)).Compute()
+			if err != nil {
+				t.Fatalf("Unexpected error occurred: %v ", err)
+			}
+			got := streamTwice(t, MA(nil, period).WithType(matype))
+			if _, err := sliceAlmostEqual(got, want, 1e-9, fmt.Sprintf("type %d period %d: ", matype, period)); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+func TestStreamingRSI(t *testing.T) {
+	for _, period := range []int{2, TESTPERIOD, 14} {
+		want, err := RSI(TestBars.Close, period).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		got := streamTwice(t, RSI(nil, period))
+		if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+			t.Errorf("period %d: %v", period, err)
+		}
+	}
+}
+
+func TestStreamingROC(t *testing.T) {
+	want, err := ROC(TestBars.Close, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got := streamTwice(t, ROC(nil, TESTPERIOD))
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamingRSL(t *testing.T) {
+	for _, period := range []int{1, 3, 8} {
+		want, err := RSL(TestBars.Close, period).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		got := streamTwice(t, RSL(nil, period))
+		if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+			t.Errorf("period %d: %v", period, err)
+		}
+	}
+}
+
+func TestStreamingTR(t *testing.T) {
+	want, err := TR(TestBars).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got := streamTwice(t, TR(BarHistory{}))
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamingATR(t *testing.T) {
+	want, err := ATR(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got := streamTwice(t, ATR(BarHistory{}, TESTPERIOD))
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	want, err = ATRP(TestBars, TESTPERIOD).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got = streamTwice(t, ATRP(BarHistory{}, TESTPERIOD))
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamingADX(t *testing.T) {
+	for _, period := range []int{TESTPERIOD, 14} {
+		want, err := ADX(TestBars, period).Compute()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		got := streamTwice(t, ADX(BarHistory{}, period))
+		if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+			t.Errorf("period %d: %v", period, err)
+		}
+	}
+}
+
+func TestStreamingBB(t *testing.T) {
+	for _, matype := range []maType{SMA, EMA, HMA, KAMA, T3, VWMA} {
+		batch := BB(TestBars.Close, 20).WithType(matype)
+		batch.Volume = volumeOf(&TestBars)
+		want, err := batch.ComputeBands()
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		bb := BB(nil, 20).WithType(matype)
+		var upper, mean, lower []float64
+		for i := 0; i < TestBars.Len(); i++ {
+			bb.Update(TestBars.Bar(i))
+			u, m, l := bb.Bands()
+			upper, mean, lower = append(upper, u), append(mean, m), append(lower, l)
+		}
+		for name, pair := range map[string][2][]float64{"upper": {upper, want.Upper}, "mean": {mean, want.Mean}, "lower": {lower, want.Lower}} {
+			if _, err := sliceAlmostEqual(pair[0], pair[1], 1e-9, name+": "); err != nil {
+				t.Errorf("type %d: %v", matype, err)
+			}
+		}
+	}
+}
+
+func TestStreamingLast(t *testing.T) {
+	want, err := Last(TestBars, Volume).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got := stream(Last(BarHistory{}, Volume))
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamingMatchesBatchTests(t *testing.T) {
+	// expected values of TestSma
+	want := []float64{nan, nan, nan, nan, 129.1708190917970, 129.53110046386700, 130.39023132324200, 131.09297790527300}
+	got := stream(MA(nil, TESTPERIOD))[:len(want)]
+	if _, err := sliceAlmostEqual(got, want, ACC); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,129 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Names of the outputs of the Supertrend.
+const (
+	SupertrendLine      = "supertrend"
+	SupertrendDirection = "direction"
+	SupertrendUpper     = "upper"
+	SupertrendLower     = "lower"
+)
+
+// SupertrendLines holds the outputs of the Supertrend. Direction is 1 in an up trend and
+// -1 in a down trend, Upper and Lower are the final bands.
+type SupertrendLines struct {
+	Supertrend []float64
+	Direction  []float64
+	Upper      []float64
+	Lower      []float64
+}
+
+// SupertrendIndicator trails the price by Multiplier times the ATR over Period bars
+// around the midpoint of the bar. In an up trend the line is the lower band, which only
+// rises, and the trend turns down when the close falls below it; in a down trend it is
+// the upper band, which only falls. The trend starts up if the first close is above the
+// midpoint. The first Period-1 values are NaN.
+type SupertrendIndicator struct {
+	BarHistoryIndicator
+	Multiplier float64
+}
+
+func Supertrend(bars BarHistory, period int, multiplier float64) *SupertrendIndicator {
+	return &SupertrendIndicator{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		Multiplier:          multiplier,
+	}
+}
+
+// Compute returns the Supertrend line.
+func (ind *SupertrendIndicator) Compute() ([]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return res.Supertrend, nil
+}
+
+// Outputs returns the names of the outputs of ComputeAll.
+func (ind *SupertrendIndicator) Outputs() []string {
+	return []string{SupertrendLine, SupertrendDirection, SupertrendUpper, SupertrendLower}
+}
+
+// ComputeAll returns the lines by output name.
+func (ind *SupertrendIndicator) ComputeAll() (map[string][]float64, error) {
+	res, err := ind.ComputeLines()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]float64{
+		SupertrendLine:      res.Supertrend,
+		SupertrendDirection: res.Direction,
+		SupertrendUpper:     res.Upper,
+		SupertrendLower:     res.Lower,
+	}, nil
+}
+
+// ComputeLines returns the line, the direction and the bands.
+func (ind *SupertrendIndicator) ComputeLines() (SupertrendLines, error) {
+	if ind.Multiplier <= 0 {
+		return SupertrendLines{}, fmt.Errorf("invalid multiplier: %v", ind.Multiplier)
+	}
+	atr, err := ATR(ind.Input, ind.Period).Compute()
+	if err != nil {
+		return SupertrendLines{}, err
+	}
+
+	n := len(atr)
+	res := SupertrendLines{
+		Supertrend: make([]float64, n),
+		Direction:  make([]float64, n),
+		Upper:      make([]float64, n),
+		Lower:      make([]float64, n),
+	}
+	bars := ind.Input
+	start := ind.Period - 1
+	for i := 0; i < n; i++ {
+		if i < start {
+			res.Supertrend[i], res.Direction[i], res.Upper[i], res.Lower[i] = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		mid := (bars.High[i] + bars.Low[i]) / 2
+		upper := mid + ind.Multiplier*atr[i]
+		lower := mid - ind.Multiplier*atr[i]
+
+		if i == start {
+			res.Upper[i], res.Lower[i] = upper, lower
+			res.Direction[i] = -1
+			if bars.Close[i] > mid {
+				res.Direction[i] = 1
+			}
+		} else {
+			// the bands only move towards the price unless the price has crossed them
+			res.Upper[i], res.Lower[i] = upper, lower
+			if upper > res.Upper[i-1] && bars.Close[i-1] <= res.Upper[i-1] {
+				res.Upper[i] = res.Upper[i-1]
+			}
+			if lower < res.Lower[i-1] && bars.Close[i-1] >= res.Lower[i-1] {
+				res.Lower[i] = res.Lower[i-1]
+			}
+
+			res.Direction[i] = res.Direction[i-1]
+			switch {
+			case res.Direction[i-1] > 0 && bars.Close[i] < res.Lower[i]:
+				res.Direction[i] = -1
+			case res.Direction[i-1] < 0 && bars.Close[i] > res.Upper[i]:
+				res.Direction[i] = 1
+			}
+		}
+
+		res.Supertrend[i] = res.Upper[i]
+		if res.Direction[i] > 0 {
+			res.Supertrend[i] = res.Lower[i]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,277 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// HeikinAshi returns the Heikin-Ashi candles of the bars. The close is the average of open,
+// high, low and close, the open the midpoint of the previous Heikin-Ashi body, starting at
+// the midpoint of the first body. High and low include the new open and close. Time and
+// volume are copied.
+func HeikinAshi(bars BarHistory) (BarHistory, error) {
+	n := bars.Len()
+	if n == 0 || len(bars.Open) != n || len(bars.High) != n || len(bars.Low) != n {
+		return BarHistory{}, fmt.Errorf("Heikin-Ashi requires open, high, low and close of every bar")
+	}
+	res := BarHistory{
+		Time:   append(bars.Time[:0:0], bars.Time...),
+		Open:   make([]float64, n),
+		High:   make([]float64, n),
+		Low:    make([]float64, n),
+		Close:  make([]float64, n),
+		Volume: append(bars.Volume[:0:0], bars.Volume...),
+	}
+	for i := 0; i < n; i++ {
+		res.Close[i] = (bars.Open[i] + bars.High[i] + bars.Low[i] + bars.Close[i]) / 4
+		if i == 0 {
+			res.Open[i] = (bars.Open[i] + bars.Close[i]) / 2
+		} else {
+			res.Open[i] = (res.Open[i-1] + res.Close[i-1]) / 2
+		}
+		res.High[i] = math.Max(bars.High[i], math.Max(res.Open[i], res.Close[i]))
+		res.Low[i] = math.Min(bars.Low[i], math.Min(res.Open[i], res.Close[i]))
+	}
+	return res, nil
+}
+
+// ATRBoxSize returns the latest ATR over period bars, a common box size for Renko and range
+// bars that adapts to the volatility of the asset.
+func ATRBoxSize(bars BarHistory, period int) (float64, error) {
+	box, err := Latest(ATR(bars, period))
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(box) || box <= 0 {
+		return 0, fmt.Errorf("invalid box size: %v", box)
+	}
+	return box, nil
+}
+
+// barBuilder collects the bars of a transform. The volume of an input bar counts towards the
+// first bar completed on or after it.
+type barBuilder struct {
+	res    BarHistory
+	volume int64
+}
+
+func (b *barBuilder) add(bars *BarHistory, i int, open, high, low, close float64) {
+	if bars.HasTime() {
+		b.res.Time = append(b.res.Time, bars.Time[i])
+	}
+	b.res.Open = append(b.res.Open, open)
+	b.res.High = append(b.res.High, high)
+	b.res.Low = append(b.res.Low, low)
+	b.res.Close = append(b.res.Close, close)
+	if len(bars.Volume) == bars.Len() {
+		b.res.Volume = append(b.res.Volume, b.volume)
+		b.volume = 0
+	}
+}
+
+func (b *barBuilder) addVolume(bars *BarHistory, i int) {
+	if len(bars.Volume) == bars.Len() {
+		b.volume += bars.Volume[i]
+	}
+}
+
+// Renko returns the Renko bricks of the closes. A brick of size box is added whenever the
+// close moves a box beyond the last brick in the direction of the trend, or two boxes
+// against it. The first brick starts at the first close. A bar may add several bricks or
+// none, each brick has the time of the bar that completed it. Only complete bricks are
+// returned.
+func Renko(bars BarHistory, box float64) (BarHistory, error) {
+	if box <= 0 {
+		return BarHistory{}, fmt.Errorf("invalid box size: %v", box)
+	}
+	if bars.Len() == 0 {
+		return BarHistory{}, fmt.Errorf("no bars")
+	}
+
+	b := barBuilder{}
+	ref, dir := bars.Close[0], 0
+	// next adds the next brick completed by the close of bar i, if any
+	next := func(i int) bool {
+		open, close := 0.0, 0.0
+		c := bars.Close[i]
+		switch {
+		case dir >= 0 && c >= ref+box:
+			open, close, dir = ref, ref+box, 1
+		case dir <= 0 && c <= ref-box:
+			open, close, dir = ref, ref-box, -1
+		case dir > 0 && c <= ref-2*box:
+			// a reversal starts at the other end of the last brick
+			open, close, dir = ref-box, ref-2*box, -1
+		case dir < 0 && c >= ref+2*box:
+			open, close, dir = ref+box, ref+2*box, 1
+		default:
+			return false
+		}
+		b.add(&bars, i, open, math.Max(open, close), math.Min(open, close), close)
+		ref = close
+		return true
+	}
+	b.addVolume(&bars, 0)
+	for i := 1; i < bars.Len(); i++ {
+		b.addVolume(&bars, i)
+		for next(i) {
+		}
+	}
+	return b.res, nil
+}
+
+// RangeBars returns bars that span box from low to high. Since the path of the price within
+// a bar is unknown, it is assumed to run from the open to the low, the high and the close if
+// the bar closes up and from the open to the high, the low and the close otherwise. A range
+// bar completes as soon as the price moves a box away from its low or high and the next one
+// opens at its close. Each range bar has the time of the bar that completed it. Only complete
+// range bars are returned.
+func RangeBars(bars BarHistory, box float64) (BarHistory, error) {
+	if box <= 0 {
+		return BarHistory{}, fmt.Errorf("invalid box size: %v", box)
+	}
+	n := bars.Len()
+	if n == 0 || len(bars.Open) != n || len(bars.High) != n || len(bars.Low) != n {
+		return BarHistory{}, fmt.Errorf("range bars require open, high, low and close of every bar")
+	}
+
+	b := barBuilder{}
+	open, high, low := bars.Open[0], bars.Open[0], bars.Open[0]
+	feed := func(i int, price float64) {
+		for {
+			switch {
+			case price > low+box:
+				high = low + box
+				b.add(&bars, i, open, high, low, high)
+				open, low = high, high
+			case price < high-box:
+				low = high - box
+				b.add(&bars, i, open, high, low, low)
+				open, high = low, low
+			default:
+				high, low = math.Max(high, price), math.Min(low, price)
+				return
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		b.addVolume(&bars, i)
+		path := []float64{bars.Open[i], bars.High[i], bars.Low[i], bars.Close[i]}
+		if bars.Close[i] >= bars.Open[i] {
+			path[1], path[2] = bars.Low[i], bars.High[i]
+		}
+		for _, price := range path {
+			feed(i, price)
+		}
+	}
+	return b.res, nil
+}
+
+type timeframe int
+
+const (
+	// Weekly groups the bars by ISO week.
+	Weekly timeframe = iota
+	// Monthly groups the bars by calendar month.
+	Monthly
+)
+
+// Resample aggregates the bars to a longer timeframe: the open of the first bar, the highest
+// high, the lowest low, the close of the last bar and the total volume. A resampled bar has
+// the time of its last bar, so the last one covers the current period up to the last bar
+// and may be incomplete. It requires the Time column of the bars.
+func Resample(bars BarHistory, tf timeframe) (BarHistory, error) {
+	res, _, err := resample(&bars, tf)
+	return res, err
+}
+
+// resample also returns the index of the last bar of every resampled bar.
+func resample(bars *BarHistory, tf timeframe) (BarHistory, []int, error) {
+	n := bars.Len()
+	if !bars.HasTime() || len(bars.Open) != n || len(bars.High) != n || len(bars.Low) != n {
+		return BarHistory{}, nil, fmt.Errorf("resampling requires time, open, high, low and close of every bar")
+	}
+	var key func(i int) int
+	switch tf {
+	case Weekly:
+		key = func(i int) int {
+			year, week := bars.Time[i].ISOWeek()
+			return year*100 + week
+		}
+	case Monthly:
+		key = func(i int) int { return bars.Time[i].Year()*100 + int(bars.Time[i].Month()) }
+	default:
+		return BarHistory{}, nil, fmt.Errorf("unknown timeframe: %d", tf)
+	}
+
+	hasVolume := len(bars.Volume) == n
+	res := BarHistory{}
+	var ends []int
+	for i := 0; i < n; i++ {
+		last := len(ends) - 1
+		if i == 0 || key(i) != key(i-1) {
+			res.Time = append(res.Time, bars.Time[i])
+			res.Open = append(res.Open, bars.Open[i])
+			res.High = append(res.High, bars.High[i])
+			res.Low = append(res.Low, bars.Low[i])
+			res.Close = append(res.Close, bars.Close[i])
+			if hasVolume {
+				res.Volume = append(res.Volume, bars.Volume[i])
+			}
+			ends = append(ends, i)
+			continue
+		}
+		res.Time[last] = bars.Time[i]
+		res.High[last] = math.Max(res.High[last], bars.High[i])
+		res.Low[last] = math.Min(res.Low[last], bars.Low[i])
+		res.Close[last] = bars.Close[i]
+		if hasVolume {
+			res.Volume[last] += bars.Volume[i]
+		}
+		ends[last] = i
+	}
+	return res, ends, nil
+}
+
+// TimeframeIndicator computes an indicator on the bars resampled to a longer timeframe, e.g.
+// a weekly trend filter on daily bars. The values are aligned with the input bars: each bar
+// has the value of the last resampled bar that ended at or before it, so a bar within a week
+// sees the value of the previous week and only the last bar sees the current, incomplete
+// week. The values are NaN until the first resampled bar ends.
+type TimeframeIndicator struct {
+	Source    Indicator
+	Timeframe timeframe
+	input     BarHistory
+}
+
+// OnTimeframe computes the indicator on the bars resampled to the timeframe.
+func OnTimeframe(ind Indicator, tf timeframe) *TimeframeIndicator {
+	return &TimeframeIndicator{Source: ind, Timeframe: tf}
+}
+
+func (ind *TimeframeIndicator) SetInput(bars *BarHistory) {
+	ind.input = *bars
+}
+
+func (ind *TimeframeIndicator) Compute() ([]float64, error) {
+	resampled, ends, err := resample(&ind.input, ind.Timeframe)
+	if err != nil {
+		return nil, err
+	}
+	ind.Source.SetInput(&resampled)
+	values, err := ind.Source.Compute()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, ind.input.Len())
+	for i := range res {
+		res[i] = math.NaN()
+	}
+	for g, end := range ends {
+		for i := end; i < len(res) && (g+1 == len(ends) || i < ends[g+1]); i++ {
+			res[i] = values[g]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,136 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// barsEqual compares the prices and volumes of two bar histories.
+func barsEqual(t *testing.T, got, want BarHistory) {
+	t.Helper()
+	for name, pair := range map[string][2][]float64{
+		"open":  {got.Open, want.Open},
+		"high":  {got.High, want.High},
+		"low":   {got.Low, want.Low},
+		"close": {got.Close, want.Close},
+	} {
+		if _, err := sliceAlmostEqual(pair[0], pair[1], 1e-9, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+	if len(got.Volume) != len(want.Volume) {
+		t.Fatalf("volume: expected %v, got %v", want.Volume, got.Volume)
+	}
+	for i := range got.Volume {
+		if got.Volume[i] != want.Volume[i] {
+			t.Errorf("volume: expected %v, got %v", want.Volume, got.Volume)
+		}
+	}
+}
+
+func TestHeikinAshi(t *testing.T) {
+	got, err := HeikinAshi(BarHistory{Open: []float64{10, 11}, High: []float64{12, 13}, Low: []float64{9, 10}, Close: []float64{11, 12}})
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	barsEqual(t, got, BarHistory{Open: []float64{10.5, 10.5}, High: []float64{12, 13}, Low: []float64{9, 10}, Close: []float64{10.5, 11.5}})
+
+	if _, err := HeikinAshi(BarHistory{Close: []float64{1}}); err == nil {
+		t.Errorf("Want error without open, high and low")
+	}
+}
+
+func TestRenko(t *testing.T) {
+	bars := BarHistory{
+		Close:  []float64{10, 10.5, 12.2, 11.5, 9.8, 8.9, 11.1},
+		Volume: []int64{1, 1, 1, 1, 1, 1, 1},
+	}
+	got, err := Renko(bars, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// two bricks up, a reversal needs two boxes, one more brick down and a reversal up
+	barsEqual(t, got, BarHistory{
+		Open:   []float64{10, 11, 11, 10, 10},
+		High:   []float64{11, 12, 11, 10, 11},
+		Low:    []float64{10, 11, 10, 9, 10},
+		Close:  []float64{11, 12, 10, 9, 11},
+		Volume: []int64{3, 0, 2, 1, 1},
+	})
+
+	if _, err := Renko(bars, 0); err == nil {
+		t.Errorf("Want error for invalid box size")
+	}
+}
+
+func TestRangeBars(t *testing.T) {
+	bars := BarHistory{Open: []float64{10, 11}, High: []float64{11, 14}, Low: []float64{9, 10.5}, Close: []float64{10.5, 13}}
+	got, err := RangeBars(bars, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	barsEqual(t, got, BarHistory{Open: []float64{10, 11}, High: []float64{11, 13}, Low: []float64{9, 11}, Close: []float64{11, 13}})
+
+	box, err := ATRBoxSize(TestBars, TESTPERIOD)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	got, err = RangeBars(TestBars, box)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for i := range got.Close {
+		if math.Abs(got.High[i]-got.Low[i]-box) > 1e-9 {
+			t.Fatalf("Expected range %v at index %d, got %v", box, i, got.High[i]-got.Low[i])
+		}
+	}
+}
+
+func TestResample(t *testing.T) {
+	bars := days("2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05", "2024-01-08", "2024-01-09", "2024-01-10")
+	got, err := Resample(bars, Weekly)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	barsEqual(t, got, BarHistory{
+		Open:   []float64{10, 15},
+		High:   []float64{15, 18},
+		Low:    []float64{9, 14},
+		Close:  []float64{14.5, 17.5},
+		Volume: []int64{1500, 2100},
+	})
+	if !got.Time[0].Equal(bars.Time[4]) || !got.Time[1].Equal(bars.Time[7]) {
+		t.Errorf("Expected the time of the last bar of the week, got %v", got.Time)
+	}
+
+	got, err = Resample(days("2024-01-30", "2024-01-31", "2024-02-01"), Monthly)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	barsEqual(t, got, BarHistory{
+		Open:   []float64{10, 12},
+		High:   []float64{12, 13},
+		Low:    []float64{9, 11},
+		Close:  []float64{11.5, 12.5},
+		Volume: []int64{300, 300},
+	})
+
+	bars.Time = nil
+	if _, err := Resample(bars, Weekly); err == nil {
+		t.Errorf("Want error without time")
+	}
+}
+
+func TestOnTimeframe(t *testing.T) {
+	bars := days("2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05", "2024-01-08", "2024-01-09", "2024-01-10")
+	weekly := OnTimeframe(Last(BarHistory{}, Close), Weekly)
+	weekly.SetInput(&bars)
+	got, err := weekly.Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// within the second week the bars see the close of the first week
+	if _, err := sliceAlmostEqual(got, []float64{nan, nan, nan, nan, 14.5, 14.5, 14.5, 17.5}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
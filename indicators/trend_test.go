@@ -0,0 +1,73 @@
+package indicators
+
+import "testing"
+
+func TestPSAR(t *testing.T) {
+	bars := BarHistory{
+		High:  append(append([]float64{}, stochBars.High...), 13),
+		Low:   append(append([]float64{}, stochBars.Low...), 7),
+		Close: append(append([]float64{}, stochBars.Close...), 8),
+	}
+	got, err := PSAR(bars, 0.02, 0.2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	// the low of the first bar caps the stop at bar 2, the new highs accelerate it and the
+	// low of the last bar reverses the trend at the extreme point
+	want := []float64{nan, 8, 8, 8.08, 8.2768, 14}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := PSAR(bars, 0.02, 0.01).Compute(); err == nil {
+		t.Errorf("Want error for max below step")
+	}
+}
+
+func TestIchimoku(t *testing.T) {
+	got, err := Ichimoku(stochBars, 2, 3, 4).WithDisplacement(1).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for name, pair := range map[string][2][]float64{
+		"tenkan":  {got.Tenkan, {nan, 10, 10.5, 11, 12}},
+		"kijun":   {got.Kijun, {nan, nan, 10, 11, 11.5}},
+		"senkouA": {got.SenkouA, {nan, nan, nan, 10.25, 11}},
+		"senkouB": {got.SenkouB, {nan, nan, nan, nan, 10.5}},
+		"chikou":  {got.Chikou, {11, 10, 12, 13, nan}},
+	} {
+		if _, err := sliceAlmostEqual(pair[0], pair[1], 1e-9, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := Ichimoku(stochBars, 2, 3, 5).Compute(); err == nil {
+		t.Errorf("Want error for too few bars")
+	}
+}
+
+func TestAroon(t *testing.T) {
+	got, err := Aroon(stochBars, 2).ComputeLines()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for name, pair := range map[string][2][]float64{
+		"up":         {got.Up, {nan, nan, 50, 100, 100}},
+		"down":       {got.Down, {nan, nan, 0, 50, 0}},
+		"oscillator": {got.Oscillator, {nan, nan, 50, 50, 100}},
+	} {
+		if _, err := sliceAlmostEqual(pair[0], pair[1], 1e-9, name+": "); err != nil {
+			t.Error(err)
+		}
+	}
+
+	ind := Output(Aroon(BarHistory{}, 2), AroonOscillator)
+	ind.SetInput(&stochBars)
+	osc, err := ind.Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(osc, got.Oscillator, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,39 @@
+package indicators
+
+import "math"
+
+// TripleExponentialAverage is the one bar rate of change in percent of the EMA of the EMA of
+// the EMA of the input over Period values. Like the EMA it has no warm-up period apart from
+// the first value, which is NaN. The rate of change is NaN as well where the previous EMA
+// is 0.
+type TripleExponentialAverage struct {
+	TimeSeriesIndicator
+}
+
+func TRIX(input []float64, period int) *TripleExponentialAverage {
+	return &TripleExponentialAverage{
+		TimeSeriesIndicator: NewTimeSeriesIndicator(input, period),
+	}
+}
+
+func (ind *TripleExponentialAverage) Compute() ([]float64, error) {
+	ema := ind.Input
+	for k := 0; k < 3; k++ {
+		var err error
+		ema, err = MA(ema, ind.Period).WithType(EMA).Compute()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]float64, len(ema))
+	res[0] = math.NaN()
+	for i := 1; i < len(res); i++ {
+		if ema[i-1] == 0 {
+			res[i] = math.NaN()
+			continue
+		}
+		res[i] = (ema[i] - ema[i-1]) / ema[i-1] * 100
+	}
+	return res, nil
+}
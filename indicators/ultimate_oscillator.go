@@ -0,0 +1,71 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// UltimateOscillator combines the buying pressure of three periods. The buying pressure of a
+// bar is the close minus the lower of the low and the previous close, relative to the true
+// range. The averages over Short, Medium and Long bars are weighted 4:2:1 and scaled to 0 to
+// 100. A period without range counts as 50. The first Long values are NaN.
+type UltimateOscillator struct {
+	BarHistoryIndicator
+	Short  int
+	Medium int
+	Long   int
+}
+
+// UO creates the Ultimate Oscillator, the common periods are 7, 14 and 28.
+func UO(bars BarHistory, short, medium, long int) *UltimateOscillator {
+	return &UltimateOscillator{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, long),
+		Short:               short,
+		Medium:              medium,
+		Long:                long,
+	}
+}
+
+func (ind *UltimateOscillator) Compute() ([]float64, error) {
+	if ind.Short <= 0 || ind.Medium < ind.Short || ind.Long < ind.Medium {
+		return nil, fmt.Errorf("invalid periods: short %d, medium %d, long %d", ind.Short, ind.Medium, ind.Long)
+	}
+	bars := ind.Input
+	err := CheckInput(bars.Close, ind.Long)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars.High) != len(bars.Close) || len(bars.Low) != len(bars.Close) {
+		return nil, fmt.Errorf("Ultimate Oscillator requires high, low and close of every bar")
+	}
+
+	n := len(bars.Close)
+	pressure := make([]float64, n)
+	ranges := make([]float64, n)
+	for i := 1; i < n; i++ {
+		low := math.Min(bars.Low[i], bars.Close[i-1])
+		pressure[i] = bars.Close[i] - low
+		ranges[i] = math.Max(bars.High[i], bars.Close[i-1]) - low
+	}
+	average := func(i, period int) float64 {
+		bp, tr := 0.0, 0.0
+		for j := i - period + 1; j <= i; j++ {
+			bp += pressure[j]
+			tr += ranges[j]
+		}
+		if tr == 0 {
+			return 0.5
+		}
+		return bp / tr
+	}
+
+	res := make([]float64, n)
+	for i := range res {
+		if i < ind.Long {
+			res[i] = math.NaN()
+			continue
+		}
+		res[i] = 100 * (4*average(i, ind.Short) + 2*average(i, ind.Medium) + average(i, ind.Long)) / 7
+	}
+	return res, nil
+}
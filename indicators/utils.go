@@ -48,6 +48,10 @@ func sliceAlmostEqual(a, b []float64, acc float64, args ...string) (bool, error)
 	}
 
 	for i := range a {
+		// NaN marks the warm-up period and must match exactly
+		if math.IsNaN(a[i]) && math.IsNaN(b[i]) {
+			continue
+		}
 		diff := math.Abs(a[i] - b[i])
 		if math.IsNaN(diff) {
 			return false, fmt.Errorf("found NaN at index %d, %v, %v", i, a[i], b[i])
@@ -58,3 +62,44 @@ func sliceAlmostEqual(a, b []float64, acc float64, args ...string) (bool, error)
 	}
 	return true, nil
 }
+
+// smooth returns the moving average of values[start:] aligned with values. The result is
+// NaN for the first start+period-1 values regardless of the type of the average. The
+// volume belongs to values and may be nil unless the average is volume weighted.
+func smooth(values, volume []float64, start, period int, matype maType) ([]float64, error) {
+	if len(volume) == len(values) {
+		volume = volume[start:]
+	} else {
+		volume = nil
+	}
+	ma, err := MA(values[start:], period).WithType(matype).WithVolume(volume).Compute()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(values))
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= start+period-1 {
+			res[i] = ma[i-start]
+		}
+	}
+	return res, nil
+}
+
+// highest returns the maximum of the window of period values ending at index i.
+func highest(values []float64, i, period int) float64 {
+	res := values[i]
+	for j := i - period + 1; j < i; j++ {
+		res = math.Max(res, values[j])
+	}
+	return res
+}
+
+// lowest returns the minimum of the window of period values ending at index i.
+func lowest(values []float64, i, period int) float64 {
+	res := values[i]
+	for j := i - period + 1; j < i; j++ {
+		res = math.Min(res, values[j])
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+type volatilityMethod int
+
+const (
+	// CloseToClose is the standard deviation of the logarithmic one bar returns.
+	CloseToClose volatilityMethod = iota
+	// Parkinson estimates the volatility from the range between high and low.
+	Parkinson
+	// GarmanKlass estimates the volatility from open, high, low and close.
+	GarmanKlass
+)
+
+// TradingDays is the number of bars per year used to annualize the volatility of daily bars.
+const TradingDays = 252
+
+// HistoricalVolatility is the annualized volatility in percent over the last Period bars,
+// estimated with Method. The standard deviations are population ones like StdDev. The
+// close-to-close volatility is NaN for the first Period values, the other methods for the
+// first Period-1 values.
+type HistoricalVolatility struct {
+	BarHistoryIndicator
+	Method volatilityMethod
+	// BarsPerYear annualizes the volatility, defaults to TradingDays.
+	BarsPerYear float64
+}
+
+func HV(bars BarHistory, period int) *HistoricalVolatility {
+	return &HistoricalVolatility{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+		BarsPerYear:         TradingDays,
+	}
+}
+
+// WithMethod sets the estimator of the volatility.
+func (ind *HistoricalVolatility) WithMethod(method volatilityMethod) *HistoricalVolatility {
+	ind.Method = method
+	return ind
+}
+
+// WithBarsPerYear sets the number of bars per year, e.g. 52 for weekly bars.
+func (ind *HistoricalVolatility) WithBarsPerYear(bars float64) *HistoricalVolatility {
+	ind.BarsPerYear = bars
+	return ind
+}
+
+func (ind *HistoricalVolatility) Compute() ([]float64, error) {
+	bars := ind.Input
+	err := CheckInput(bars.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	if ind.BarsPerYear <= 0 {
+		return nil, fmt.Errorf("invalid bars per year: %v", ind.BarsPerYear)
+	}
+
+	n := len(bars.Close)
+	// variance of a single bar
+	variance := make([]float64, n)
+	switch ind.Method {
+	case CloseToClose:
+		logReturns := make([]float64, n)
+		for i := 1; i < n; i++ {
+			logReturns[i] = math.Log(bars.Close[i] / bars.Close[i-1])
+		}
+		res := make([]float64, n)
+		for i := range res {
+			res[i] = math.NaN()
+			if i >= ind.Period {
+				res[i] = StdDev(logReturns[i-ind.Period+1:i+1]) * math.Sqrt(ind.BarsPerYear) * 100
+			}
+		}
+		return res, nil
+	case Parkinson:
+		if len(bars.High) != n || len(bars.Low) != n {
+			return nil, fmt.Errorf("Parkinson volatility requires high and low of every bar")
+		}
+		for i := range variance {
+			hl := math.Log(bars.High[i] / bars.Low[i])
+			variance[i] = hl * hl / (4 * math.Ln2)
+		}
+	case GarmanKlass:
+		if len(bars.Open) != n || len(bars.High) != n || len(bars.Low) != n {
+			return nil, fmt.Errorf("Garman-Klass volatility requires open, high, low and close of every bar")
+		}
+		for i := range variance {
+			hl := math.Log(bars.High[i] / bars.Low[i])
+			co := math.Log(bars.Close[i] / bars.Open[i])
+			variance[i] = 0.5*hl*hl - (2*math.Ln2-1)*co*co
+		}
+	default:
+		return nil, fmt.Errorf("unknown volatility method: %d", ind.Method)
+	}
+
+	res := make([]float64, n)
+	for i := range res {
+		res[i] = math.NaN()
+		if i >= ind.Period-1 {
+			// the Garman-Klass variance of a bar may be negative
+			res[i] = math.Sqrt(math.Max(Mean(variance[i-ind.Period+1:i+1]), 0)*ind.BarsPerYear) * 100
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,293 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// checkVolume returns an error unless every bar has a high, low, close and volume.
+func checkVolume(bars *BarHistory) error {
+	n := len(bars.Close)
+	if n == 0 {
+		return errors.New("no bars")
+	}
+	if len(bars.High) != n || len(bars.Low) != n || len(bars.Volume) != n {
+		return errors.New("volume indicators require high, low, close and volume of every bar")
+	}
+	return nil
+}
+
+func typicalPrice(bars *BarHistory, i int) float64 {
+	return (bars.High[i] + bars.Low[i] + bars.Close[i]) / 3
+}
+
+// moneyFlowVolume is the volume weighted by the location of the close within the range of
+// the bar, from -1 at the low to 1 at the high.
+func moneyFlowVolume(bars *BarHistory, i int) float64 {
+	hl := bars.High[i] - bars.Low[i]
+	if hl == 0 {
+		return 0
+	}
+	return ((bars.Close[i] - bars.Low[i]) - (bars.High[i] - bars.Close[i])) / hl * float64(bars.Volume[i])
+}
+
+// OnBalanceVolume adds the volume of up days and subtracts the one of down days. It starts
+// at 0 and has no warm-up period.
+type OnBalanceVolume struct {
+	BarHistoryIndicator
+}
+
+func OBV(bars BarHistory) *OnBalanceVolume {
+	return &OnBalanceVolume{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
+	}
+}
+
+func (ind *OnBalanceVolume) Compute() ([]float64, error) {
+	if len(ind.Input.Close) == 0 || len(ind.Input.Volume) != len(ind.Input.Close) {
+		return nil, errors.New("OBV requires close and volume of every bar")
+	}
+	res := make([]float64, len(ind.Input.Close))
+	for i := 1; i < len(res); i++ {
+		res[i] = res[i-1]
+		switch {
+		case ind.Input.Close[i] > ind.Input.Close[i-1]:
+			res[i] += float64(ind.Input.Volume[i])
+		case ind.Input.Close[i] < ind.Input.Close[i-1]:
+			res[i] -= float64(ind.Input.Volume[i])
+		}
+	}
+	return res, nil
+}
+
+// VolumeWeightedAveragePrice is the average of the typical price (high+low+close)/3
+// weighted by volume. The rolling VWAP averages the last Period bars and is NaN for the
+// first Period-1 values. The anchored VWAP averages all bars since Anchor and is NaN before.
+// Windows without volume are NaN.
+type VolumeWeightedAveragePrice struct {
+	BarHistoryIndicator
+	// Anchor is the start of the anchored VWAP, the zero time selects the rolling VWAP.
+	Anchor time.Time
+}
+
+// VWAP creates the rolling VWAP over period bars.
+func VWAP(bars BarHistory, period int) *VolumeWeightedAveragePrice {
+	return &VolumeWeightedAveragePrice{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+// AnchoredVWAP creates the VWAP of all bars starting at or after anchor. It requires the
+// Time column of the bars.
+func AnchoredVWAP(bars BarHistory, anchor time.Time) *VolumeWeightedAveragePrice {
+	return &VolumeWeightedAveragePrice{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
+		Anchor:              anchor,
+	}
+}
+
+func (ind *VolumeWeightedAveragePrice) Compute() ([]float64, error) {
+	if err := checkVolume(&ind.Input); err != nil {
+		return nil, err
+	}
+	if ind.Anchor.IsZero() {
+		return ind.rolling()
+	}
+	return ind.anchored()
+}
+
+func (ind *VolumeWeightedAveragePrice) rolling() ([]float64, error) {
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ind.Input.Close))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		pv, volume := 0.0, 0.0
+		for j := i - ind.Period + 1; j <= i; j++ {
+			pv += typicalPrice(&ind.Input, j) * float64(ind.Input.Volume[j])
+			volume += float64(ind.Input.Volume[j])
+		}
+		res[i] = vwap(pv, volume)
+	}
+	return res, nil
+}
+
+func (ind *VolumeWeightedAveragePrice) anchored() ([]float64, error) {
+	if !ind.Input.HasTime() {
+		return nil, errors.New("anchored VWAP requires the time of every bar")
+	}
+	res := make([]float64, len(ind.Input.Close))
+	pv, volume := 0.0, 0.0
+	for i := range res {
+		if ind.Input.Time[i].Before(ind.Anchor) {
+			res[i] = math.NaN()
+			continue
+		}
+		pv += typicalPrice(&ind.Input, i) * float64(ind.Input.Volume[i])
+		volume += float64(ind.Input.Volume[i])
+		res[i] = vwap(pv, volume)
+	}
+	return res, nil
+}
+
+func vwap(pv, volume float64) float64 {
+	if volume == 0 {
+		return math.NaN()
+	}
+	return pv / volume
+}
+
+// MoneyFlowIndex is the RSI of the money flow, the typical price times the volume. It is
+// 100 if the money flow did not fall within the last Period bars and 50 if it neither rose
+// nor fell. The first Period values are NaN.
+type MoneyFlowIndex struct {
+	BarHistoryIndicator
+}
+
+func MFI(bars BarHistory, period int) *MoneyFlowIndex {
+	return &MoneyFlowIndex{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+func (ind *MoneyFlowIndex) Compute() ([]float64, error) {
+	if err := checkVolume(&ind.Input); err != nil {
+		return nil, err
+	}
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(ind.Input.Close)
+	positive := make([]float64, n)
+	negative := make([]float64, n)
+	for i := 1; i < n; i++ {
+		tp, prev := typicalPrice(&ind.Input, i), typicalPrice(&ind.Input, i-1)
+		flow := tp * float64(ind.Input.Volume[i])
+		switch {
+		case tp > prev:
+			positive[i] = flow
+		case tp < prev:
+			negative[i] = flow
+		}
+	}
+
+	res := make([]float64, n)
+	for i := range res {
+		if i < ind.Period {
+			res[i] = math.NaN()
+			continue
+		}
+		pos, neg := 0.0, 0.0
+		for j := i - ind.Period + 1; j <= i; j++ {
+			pos += positive[j]
+			neg += negative[j]
+		}
+		switch {
+		case neg == 0 && pos == 0:
+			res[i] = 50
+		case neg == 0:
+			res[i] = 100
+		default:
+			res[i] = 100 - 100/(1+pos/neg)
+		}
+	}
+	return res, nil
+}
+
+// ChaikinMoneyFlow is the sum of the money flow volume over the last Period bars divided by
+// their volume. The money flow volume weights the volume of a bar by the location of the
+// close within its range, from -1 at the low to 1 at the high. The first Period-1 values
+// are NaN.
+type ChaikinMoneyFlow struct {
+	BarHistoryIndicator
+}
+
+func CMF(bars BarHistory, period int) *ChaikinMoneyFlow {
+	return &ChaikinMoneyFlow{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+func (ind *ChaikinMoneyFlow) Compute() ([]float64, error) {
+	if err := checkVolume(&ind.Input); err != nil {
+		return nil, err
+	}
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(ind.Input.Close))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		mfv, volume := 0.0, 0.0
+		for j := i - ind.Period + 1; j <= i; j++ {
+			mfv += moneyFlowVolume(&ind.Input, j)
+			volume += float64(ind.Input.Volume[j])
+		}
+		res[i] = math.NaN()
+		if volume != 0 {
+			res[i] = mfv / volume
+		}
+	}
+	return res, nil
+}
+
+// AccumulationDistribution is the cumulative money flow volume, see ChaikinMoneyFlow. It
+// has no warm-up period.
+type AccumulationDistribution struct {
+	BarHistoryIndicator
+}
+
+func AD(bars BarHistory) *AccumulationDistribution {
+	return &AccumulationDistribution{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, 0),
+	}
+}
+
+func (ind *AccumulationDistribution) Compute() ([]float64, error) {
+	if err := checkVolume(&ind.Input); err != nil {
+		return nil, err
+	}
+	res := make([]float64, len(ind.Input.Close))
+	sum := 0.0
+	for i := range res {
+		sum += moneyFlowVolume(&ind.Input, i)
+		res[i] = sum
+	}
+	return res, nil
+}
+
+// AverageDollarVolume is the average of close times volume over the last Period bars, a
+// common measure of liquidity. The first Period-1 values are NaN.
+type AverageDollarVolume struct {
+	BarHistoryIndicator
+}
+
+func DollarVolume(bars BarHistory, period int) *AverageDollarVolume {
+	return &AverageDollarVolume{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+func (ind *AverageDollarVolume) Compute() ([]float64, error) {
+	if len(ind.Input.Volume) != len(ind.Input.Close) {
+		return nil, errors.New("dollar volume requires close and volume of every bar")
+	}
+	dollars := make([]float64, len(ind.Input.Close))
+	for i := range dollars {
+		dollars[i] = ind.Input.Close[i] * float64(ind.Input.Volume[i])
+	}
+	return MA(dollars, ind.Period).Compute()
+}
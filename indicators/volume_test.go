@@ -0,0 +1,110 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+)
+
+var volumeBars = BarHistory{
+	Time: []time.Time{
+		day(12), day(13), day(14), day(15),
+	},
+	Open:   []float64{9, 9, 11, 10},
+	High:   []float64{10, 12, 11, 13},
+	Low:    []float64{8, 9, 9, 10},
+	Close:  []float64{9, 11, 10, 12},
+	Volume: []int64{100, 200, 150, 300},
+}
+
+func TestOBV(t *testing.T) {
+	got, err := OBV(volumeBars).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{0, 200, 50, 350}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	got, err := VWAP(volumeBars, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, (900 + 32./3*200) / 300, (32./3*200 + 1500) / 350, (1500 + 3500) / 450.}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAnchoredVWAP(t *testing.T) {
+	got, err := AnchoredVWAP(volumeBars, volumeBars.Time[1]).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, 32. / 3, (32./3*200 + 1500) / 350, (32./3*200 + 1500 + 3500) / 650}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	noTime := volumeBars
+	noTime.Time = nil
+	if _, err := AnchoredVWAP(noTime, volumeBars.Time[1]).Compute(); err == nil {
+		t.Errorf("Want error for bars without time")
+	}
+}
+
+func TestMFI(t *testing.T) {
+	got, err := MFI(volumeBars, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	pos := 32. / 3 * 200
+	want := []float64{nan, nan, 100 - 100/(1+pos/1500), 100 - 100/(1+3500./1500)}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCMF(t *testing.T) {
+	got, err := CMF(volumeBars, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{nan, 200. / 3 / 300, 200. / 3 / 350, 100. / 450}
+	if _, err := sliceAlmostEqual(got, want, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAD(t *testing.T) {
+	got, err := AD(volumeBars).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{0, 200. / 3, 200. / 3, 500. / 3}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDollarVolume(t *testing.T) {
+	got, err := DollarVolume(volumeBars, 2).Compute()
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if _, err := sliceAlmostEqual(got, []float64{nan, 1550, 1850, 2550}, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVolumeIndicatorsWithoutVolume(t *testing.T) {
+	bars := volumeBars
+	bars.Volume = nil
+	for name, ind := range map[string]Indicator{
+		"OBV": OBV(bars), "VWAP": VWAP(bars, 2), "MFI": MFI(bars, 2), "CMF": CMF(bars, 2), "AD": AD(bars), "ADV": DollarVolume(bars, 2),
+	} {
+		if _, err := ind.Compute(); err == nil {
+			t.Errorf("%s: want error without volume", name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// WilliamsPercentR locates the close within the range of the last Period bars on a scale
+// from -100 at the lowest low to 0 at the highest high. If the range is empty the value is
+// -50. The first Period-1 values are NaN.
+type WilliamsPercentR struct {
+	BarHistoryIndicator
+}
+
+func WilliamsR(bars BarHistory, period int) *WilliamsPercentR {
+	return &WilliamsPercentR{
+		BarHistoryIndicator: NewBarHistoryIndicator(bars, period),
+	}
+}
+
+func (ind *WilliamsPercentR) Compute() ([]float64, error) {
+	err := CheckInput(ind.Input.Close, ind.Period)
+	if err != nil {
+		return nil, err
+	}
+	if len(ind.Input.High) != len(ind.Input.Close) || len(ind.Input.Low) != len(ind.Input.Close) {
+		return nil, fmt.Errorf("Williams %%R requires high, low and close of every bar")
+	}
+
+	res := make([]float64, len(ind.Input.Close))
+	for i := range res {
+		if i < ind.Period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		hh, ll := highest(ind.Input.High, i, ind.Period), lowest(ind.Input.Low, i, ind.Period)
+		if hh == ll {
+			res[i] = -50
+			continue
+		}
+		res[i] = -100 * (hh - ind.Input.Close[i]) / (hh - ll)
+	}
+	return res, nil
+}
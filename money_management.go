@@ -0,0 +1,135 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+)
+
+// MoneyManager determines the size of new positions.
+type MoneyManager interface {
+	// Size returns the number of shares to buy of the asset at the close of the last bar.
+	// The trading system rounds the result down to whole shares and caps it by the
+	// remaining buying power.
+	Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error)
+}
+
+// Account is the state of the account at the time the orders of a day are prepared.
+type Account struct {
+	Equity       float64
+	BuyingPower  float64
+	MaxPositions int
+	OpenSlots    int
+}
+
+// EqualWeight invests an equal share of the equity in every position.
+type EqualWeight struct{}
+
+func (mm EqualWeight) Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error) {
+	if account.MaxPositions <= 0 {
+		return 0, fmt.Errorf("invalid number of positions: %d", account.MaxPositions)
+	}
+	price, err := entryPrice(asset, bars)
+	if err != nil {
+		return 0, err
+	}
+	return account.Equity / float64(account.MaxPositions) / price, nil
+}
+
+// FixedDollar invests a fixed amount in every position.
+type FixedDollar struct {
+	Amount float64
+}
+
+func (mm FixedDollar) Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error) {
+	if mm.Amount <= 0 {
+		return 0, fmt.Errorf("invalid amount: %v", mm.Amount)
+	}
+	price, err := entryPrice(asset, bars)
+	if err != nil {
+		return 0, err
+	}
+	return mm.Amount / price, nil
+}
+
+// FixedFractional risks RiskPercent of the equity per position. The risk per share is the
+// distance to the initial stop, either StopPercent of the price or Multiple times ATR(Period).
+type FixedFractional struct {
+	RiskPercent float64
+	StopPercent float64
+	Period      int
+	Multiple    float64
+}
+
+func (mm FixedFractional) Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error) {
+	if mm.RiskPercent <= 0 {
+		return 0, fmt.Errorf("invalid risk: %v", mm.RiskPercent)
+	}
+
+	var distance float64
+	switch {
+	case mm.Multiple > 0:
+		atr, err := lastATR(bars, mm.Period)
+		if err != nil {
+			return 0, err
+		}
+		distance = mm.Multiple * atr
+	case mm.StopPercent > 0:
+		price, err := entryPrice(asset, bars)
+		if err != nil {
+			return 0, err
+		}
+		distance = price * mm.StopPercent / 100
+	default:
+		return 0, fmt.Errorf("invalid stop: neither percent nor ATR multiple set")
+	}
+	if distance <= 0 {
+		return 0, fmt.Errorf("invalid stop distance for %s: %v", asset.Symbol, distance)
+	}
+
+	return account.Equity * mm.RiskPercent / 100 / distance, nil
+}
+
+// VolatilityTarget sizes every position such that its average daily move, measured by
+// ATRP(Period), amounts to TargetPercent of the equity.
+type VolatilityTarget struct {
+	TargetPercent float64
+	Period        int
+}
+
+func (mm VolatilityTarget) Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error) {
+	if mm.TargetPercent <= 0 {
+		return 0, fmt.Errorf("invalid target: %v", mm.TargetPercent)
+	}
+	if mm.Period <= 0 || bars.Len() <= mm.Period {
+		return 0, fmt.Errorf("invalid ATRP period: %d for %d bars", mm.Period, bars.Len())
+	}
+	volatility, err := indicators.Latest(indicators.ATRP(*bars, mm.Period))
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(volatility) || volatility <= 0 {
+		return 0, fmt.Errorf("invalid volatility for %s: %v", asset.Symbol, volatility)
+	}
+
+	price, err := entryPrice(asset, bars)
+	if err != nil {
+		return 0, err
+	}
+	value := account.Equity * mm.TargetPercent / volatility
+	return value / price, nil
+}
+
+// entryPrice returns the close of the last bar, the price new positions are sized at.
+func entryPrice(asset utils.Asset, bars *indicators.BarHistory) (float64, error) {
+	price, err := lastClose(bars)
+	if err != nil {
+		return 0, err
+	}
+	if !isPositive(price) {
+		return 0, fmt.Errorf("invalid price of %s: %v", asset.Symbol, price)
+	}
+	return price, nil
+}
@@ -0,0 +1,124 @@
+package gofin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+)
+
+var testAccount = Account{Equity: 10000, BuyingPower: 10000, MaxPositions: 4, OpenSlots: 4}
+
+func TestEqualWeight(t *testing.T) {
+	got, err := EqualWeight{}.Size(utils.Asset{Symbol: "A"}, testHistory(50), testAccount)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if got != 50 {
+		t.Errorf("Expected 50 shares, got %v", got)
+	}
+}
+
+func TestFixedDollar(t *testing.T) {
+	got, _ := FixedDollar{Amount: 1000}.Size(utils.Asset{Symbol: "A"}, testHistory(50), testAccount)
+	if got != 20 {
+		t.Errorf("Expected 20 shares, got %v", got)
+	}
+	if _, err := (FixedDollar{}).Size(utils.Asset{Symbol: "A"}, testHistory(50), testAccount); err == nil {
+		t.Errorf("Want error for invalid amount")
+	}
+}
+
+func TestFixedFractional(t *testing.T) {
+	// risk 100 with a stop 5 below the price
+	mm := FixedFractional{RiskPercent: 1, StopPercent: 10}
+	got, _ := mm.Size(utils.Asset{Symbol: "A"}, testHistory(50), testAccount)
+	if got != 20 {
+		t.Errorf("Expected 20 shares, got %v", got)
+	}
+
+	// true range is 2, hence the stop is 4 below the price
+	mm = FixedFractional{RiskPercent: 1, Period: 3, Multiple: 2}
+	got, _ = mm.Size(utils.Asset{Symbol: "A"}, testHistory(50, 50, 50, 50, 50), testAccount)
+	if got != 25 {
+		t.Errorf("Expected 25 shares, got %v", got)
+	}
+}
+
+func TestVolatilityTarget(t *testing.T) {
+	// ATRP is 4%, hence a 0.5% target gives a position value of 1250
+	mm := VolatilityTarget{TargetPercent: 0.5, Period: 3}
+	got, err := mm.Size(utils.Asset{Symbol: "A"}, testHistory(50, 50, 50, 50, 50), testAccount)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if math.Abs(got-25) > 1e-9 {
+		t.Errorf("Expected 25 shares, got %v", got)
+	}
+}
+
+func TestPrepareOrdersRespectsBuyingPower(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"A": testHistory(10, 10),
+		"B": testHistory(20, 20),
+	}
+	watchlist := utils.NewWatchlist([]utils.Asset{{Symbol: "A"}, {Symbol: "B"}}, nil, nil)
+	bt, _ := NewBacktest(watchlist, history, 1000, 2)
+	bt.SetLookback(2)
+	bt.replay.day = 1
+	bt.System().SetMoneyManager(FixedDollar{Amount: 800})
+
+	orders := bt.System().prepareOrders(watchlist.Assets, history, 0)
+	if len(orders) != 2 {
+		t.Fatalf("Expected 2 orders, got %d", len(orders))
+	}
+	if orders[0].Qty.IntPart() != 80 || orders[1].Qty.IntPart() != 10 {
+		t.Errorf("Expected 80 and 10 shares, got %s and %s", orders[0].Qty, orders[1].Qty)
+	}
+}
+
+func TestMoneyManagersRejectInvalidPrice(t *testing.T) {
+	for name, bars := range map[string]*indicators.BarHistory{
+		"zero close": testHistory(0),
+		"no bars":    &indicators.BarHistory{},
+	} {
+		if _, err := (EqualWeight{}).Size(utils.Asset{Symbol: "A"}, bars, testAccount); err == nil {
+			t.Errorf("%s: want error of EqualWeight", name)
+		}
+		if _, err := (FixedDollar{Amount: 1000}).Size(utils.Asset{Symbol: "A"}, bars, testAccount); err == nil {
+			t.Errorf("%s: want error of FixedDollar", name)
+		}
+	}
+}
+
+// fixedSize returns the same size for every asset.
+type fixedSize float64
+
+func (mm fixedSize) Size(asset utils.Asset, bars *indicators.BarHistory, account Account) (float64, error) {
+	return float64(mm), nil
+}
+
+func TestPrepareOrdersSkipsInvalidSize(t *testing.T) {
+	history := map[string]*indicators.BarHistory{
+		"A": testHistory(0, 0),
+		"B": testHistory(20, 20),
+	}
+	watchlist := utils.NewWatchlist([]utils.Asset{{Symbol: "A"}, {Symbol: "B"}}, nil, nil)
+	bt, _ := NewBacktest(watchlist, history, 1000, 2)
+	bt.SetLookback(2)
+	bt.replay.day = 1
+
+	for _, size := range []float64{math.Inf(1), math.NaN(), -1} {
+		bt.System().SetMoneyManager(fixedSize(size))
+		if orders := bt.System().prepareOrders(watchlist.Assets, history, 0); len(orders) != 0 {
+			t.Errorf("Expected no orders for size %v, got %+v", size, orders)
+		}
+	}
+	// A has no valid price
+	bt.System().SetMoneyManager(fixedSize(10))
+	orders := bt.System().prepareOrders(watchlist.Assets, history, 0)
+	if len(orders) != 1 || orders[0].Symbol != "B" {
+		t.Errorf("Expected an order of B only, got %+v", orders)
+	}
+}
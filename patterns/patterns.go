@@ -0,0 +1,235 @@
+// Package patterns detects candlestick patterns in a bar history. A detector emits a signal
+// per bar, 1 for a bullish pattern, -1 for a bearish one and 0 otherwise. It satisfies
+// indicators.Indicator and can be used in watchlist filters.
+package patterns
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/d1l1x/gofin/indicators"
+)
+
+type Pattern int
+
+const (
+	// Doji has almost no body. It is bullish after a decline and bearish after a rise.
+	Doji Pattern = iota
+	// Hammer has a small body at the top of the range and a long lower shadow. It is a
+	// bullish hammer after a decline and a bearish hanging man after a rise.
+	Hammer
+	// InvertedHammer has a small body at the bottom of the range and a long upper shadow. It
+	// is a bullish inverted hammer after a decline and a bearish shooting star after a rise.
+	InvertedHammer
+	// Engulfing is a candle whose body engulfs the opposite body of the previous candle, its
+	// direction is the one of the second candle.
+	Engulfing
+	// Harami is a candle whose body lies within the larger opposite body of the previous
+	// candle, its direction is the one of the second candle.
+	Harami
+	// Star is the bullish morning star or the bearish evening star: a long candle, a small
+	// body beyond its close and an opposite candle that closes beyond the middle of the
+	// first body.
+	Star
+	// ThreeSoldiersCrows is three bullish candles with rising closes (soldiers) or three
+	// bearish candles with falling closes (crows), each opening within the previous body.
+	ThreeSoldiersCrows
+	// InsideBar has its range within the range of the previous bar, its direction is the one
+	// of the candle.
+	InsideBar
+	// OutsideBar has a range exceeding the range of the previous bar on both sides, its
+	// direction is the one of the candle.
+	OutsideBar
+)
+
+const (
+	// dojiBody is the largest body of a doji relative to its range.
+	dojiBody = 0.1
+	// shadowFactor is the length of the long shadow of a hammer relative to its body.
+	shadowFactor = 2
+	// starBody is the largest body of the middle candle of a star relative to the first body.
+	starBody = 0.3
+	// DefaultTrendBars is the number of bars that define the trend preceding a pattern.
+	DefaultTrendBars = 5
+)
+
+// Detector detects one or more patterns. If several patterns are given the signal at a bar
+// is the one of the first pattern that occurs. The trend preceding a doji or a hammer is the
+// change of the close over the TrendBars bars before the pattern. The values are NaN until
+// every pattern can be evaluated.
+type Detector struct {
+	Input     indicators.BarHistory
+	Patterns  []Pattern
+	TrendBars int
+}
+
+// Detect creates a detector of the patterns.
+func Detect(bars indicators.BarHistory, patterns ...Pattern) *Detector {
+	return &Detector{
+		Input:     bars,
+		Patterns:  patterns,
+		TrendBars: DefaultTrendBars,
+	}
+}
+
+// WithTrendBars sets the number of bars that define the preceding trend.
+func (d *Detector) WithTrendBars(bars int) *Detector {
+	d.TrendBars = bars
+	return d
+}
+
+func (d *Detector) SetInput(bars *indicators.BarHistory) {
+	d.Input = *bars
+}
+
+func (d *Detector) Compute() ([]float64, error) {
+	if len(d.Patterns) == 0 {
+		return nil, fmt.Errorf("no patterns")
+	}
+	if d.TrendBars <= 0 {
+		return nil, fmt.Errorf("invalid trend bars: %d", d.TrendBars)
+	}
+	bars := &d.Input
+	n := bars.Len()
+	if n == 0 || len(bars.Open) != n || len(bars.High) != n || len(bars.Low) != n {
+		return nil, fmt.Errorf("candlestick patterns require open, high, low and close of every bar")
+	}
+
+	start := 0
+	for _, pattern := range d.Patterns {
+		bars, err := d.warmUp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if bars > start {
+			start = bars
+		}
+	}
+	if start >= n {
+		return nil, fmt.Errorf("not enough bars: %d <= %d", n, start)
+	}
+
+	res := make([]float64, n)
+	for i := range res {
+		if i < start {
+			res[i] = math.NaN()
+			continue
+		}
+		for _, pattern := range d.Patterns {
+			if signal := d.detect(pattern, i); signal != 0 {
+				res[i] = signal
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// warmUp returns the number of bars before the first bar the pattern can be detected at.
+func (d *Detector) warmUp(pattern Pattern) (int, error) {
+	switch pattern {
+	case Doji, Hammer, InvertedHammer:
+		return d.TrendBars + 1, nil
+	case Engulfing, Harami, InsideBar, OutsideBar:
+		return 1, nil
+	case Star, ThreeSoldiersCrows:
+		return 2, nil
+	}
+	return 0, fmt.Errorf("unknown pattern: %d", pattern)
+}
+
+func (d *Detector) detect(pattern Pattern, i int) float64 {
+	bars := &d.Input
+	cur := candle(bars.Bar(i))
+	switch pattern {
+	case Doji:
+		if cur.body() <= dojiBody*cur.span() {
+			return -d.trend(i)
+		}
+	case Hammer:
+		if cur.lower() > 0 && cur.lower() >= shadowFactor*cur.body() && cur.upper() <= cur.body() {
+			return -d.trend(i)
+		}
+	case InvertedHammer:
+		if cur.upper() > 0 && cur.upper() >= shadowFactor*cur.body() && cur.lower() <= cur.body() {
+			return -d.trend(i)
+		}
+	case Engulfing:
+		prev := candle(bars.Bar(i - 1))
+		if cur.direction() == -prev.direction() && cur.top() >= prev.top() && cur.bottom() <= prev.bottom() && cur.body() > prev.body() {
+			return cur.direction()
+		}
+	case Harami:
+		prev := candle(bars.Bar(i - 1))
+		if cur.direction() == -prev.direction() && cur.top() <= prev.top() && cur.bottom() >= prev.bottom() && cur.body() < prev.body() {
+			return cur.direction()
+		}
+	case Star:
+		first, middle := candle(bars.Bar(i-2)), candle(bars.Bar(i-1))
+		dir := cur.direction()
+		if dir == 0 || first.direction() != -dir || middle.body() > starBody*first.body() {
+			return 0
+		}
+		// the middle body lies beyond the close of the first candle, the last candle closes
+		// beyond the middle of the first body
+		mid := (first.Open + first.Close) / 2
+		if dir > 0 && middle.top() <= first.Close && cur.Close > mid {
+			return 1
+		}
+		if dir < 0 && middle.bottom() >= first.Close && cur.Close < mid {
+			return -1
+		}
+	case ThreeSoldiersCrows:
+		dir := cur.direction()
+		if dir == 0 {
+			return 0
+		}
+		for j := i - 1; j <= i; j++ {
+			prev, next := candle(bars.Bar(j-1)), candle(bars.Bar(j))
+			if prev.direction() != dir || next.direction() != dir || (next.Close-prev.Close)*dir <= 0 ||
+				next.Open < prev.bottom() || next.Open > prev.top() {
+				return 0
+			}
+		}
+		return dir
+	case InsideBar:
+		if bars.High[i] < bars.High[i-1] && bars.Low[i] > bars.Low[i-1] {
+			return cur.direction()
+		}
+	case OutsideBar:
+		if bars.High[i] > bars.High[i-1] && bars.Low[i] < bars.Low[i-1] {
+			return cur.direction()
+		}
+	}
+	return 0
+}
+
+// trend returns the sign of the change of the close over the TrendBars bars before bar i.
+func (d *Detector) trend(i int) float64 {
+	return sign(d.Input.Close[i-1] - d.Input.Close[i-1-d.TrendBars])
+}
+
+// candle adds the shape of the candle to a bar.
+type candle indicators.Bar
+
+func (c candle) top() float64    { return math.Max(c.Open, c.Close) }
+func (c candle) bottom() float64 { return math.Min(c.Open, c.Close) }
+func (c candle) body() float64   { return c.top() - c.bottom() }
+func (c candle) span() float64   { return c.High - c.Low }
+func (c candle) upper() float64  { return c.High - c.top() }
+func (c candle) lower() float64  { return c.bottom() - c.Low }
+
+// direction is 1 for a bullish and -1 for a bearish candle.
+func (c candle) direction() float64 { return sign(c.Close - c.Open) }
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+var _ indicators.Indicator = (*Detector)(nil)
@@ -0,0 +1,93 @@
+package patterns
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/d1l1x/gofin/indicators"
+)
+
+var nan = math.NaN()
+
+// candles creates bars from open, high, low and close.
+func candles(ohlc ...[4]float64) indicators.BarHistory {
+	var bars indicators.BarHistory
+	for _, c := range ohlc {
+		bars.Open = append(bars.Open, c[0])
+		bars.High = append(bars.High, c[1])
+		bars.Low = append(bars.Low, c[2])
+		bars.Close = append(bars.Close, c[3])
+	}
+	return bars
+}
+
+func signalsEqual(got, want []float64) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("expected %d signals, got %d", len(want), len(got))
+	}
+	for i := range got {
+		if got[i] != want[i] && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+			return fmt.Errorf("expected %v, got %v", want, got)
+		}
+	}
+	return nil
+}
+
+func TestPatterns(t *testing.T) {
+	decline := [][4]float64{{12, 12.2, 11.8, 12}, {11.5, 11.7, 10.9, 11}, {10.5, 10.6, 9.9, 10}}
+	rise := [][4]float64{{8, 8.2, 7.8, 8}, {8.5, 9.1, 8.3, 9}, {9.5, 10.1, 9.4, 10}}
+	hammer := [4]float64{9.8, 10, 8, 9.9}
+	shootingStar := [4]float64{10.1, 12, 10, 10.2}
+
+	tests := []struct {
+		name     string
+		bars     indicators.BarHistory
+		patterns []Pattern
+		want     []float64
+	}{
+		{"bullish engulfing", candles([4]float64{10, 10.5, 8.5, 9}, [4]float64{8.8, 10.6, 8.7, 10.2}), []Pattern{Engulfing}, []float64{nan, 1}},
+		{"bearish engulfing", candles([4]float64{9, 10.5, 8.5, 10}, [4]float64{10.2, 10.6, 8.7, 8.8}), []Pattern{Engulfing}, []float64{nan, -1}},
+		{"no engulfing", candles([4]float64{10, 10.5, 8.5, 9}, [4]float64{9.2, 10, 9, 9.8}), []Pattern{Engulfing}, []float64{nan, 0}},
+		{"bullish harami", candles([4]float64{10, 10.2, 7.8, 8}, [4]float64{8.5, 9.2, 8.4, 9}), []Pattern{Harami}, []float64{nan, 1}},
+		{"bearish harami", candles([4]float64{8, 10.2, 7.8, 10}, [4]float64{9.5, 9.6, 8.8, 9}), []Pattern{Harami}, []float64{nan, -1}},
+		{"morning star", candles([4]float64{10, 10.2, 7.9, 8}, [4]float64{7.8, 8, 7.5, 7.7}, [4]float64{7.9, 9.6, 7.8, 9.5}), []Pattern{Star}, []float64{nan, nan, 1}},
+		{"evening star", candles([4]float64{8, 10.1, 7.8, 10}, [4]float64{10.2, 10.5, 10, 10.3}, [4]float64{10.1, 10.2, 8.4, 8.5}), []Pattern{Star}, []float64{nan, nan, -1}},
+		{"weak morning star", candles([4]float64{10, 10.2, 7.9, 8}, [4]float64{7.8, 8, 7.5, 7.7}, [4]float64{7.9, 8.6, 7.8, 8.5}), []Pattern{Star}, []float64{nan, nan, 0}},
+		{"three soldiers", candles([4]float64{10, 11.1, 9.9, 11}, [4]float64{10.5, 12.1, 10.4, 12}, [4]float64{11.5, 13.1, 11.4, 13}), []Pattern{ThreeSoldiersCrows}, []float64{nan, nan, 1}},
+		{"three crows", candles([4]float64{13, 13.1, 11.9, 12}, [4]float64{12.5, 12.6, 10.9, 11}, [4]float64{11.5, 11.6, 9.9, 10}), []Pattern{ThreeSoldiersCrows}, []float64{nan, nan, -1}},
+		{"gap up soldiers", candles([4]float64{10, 11.1, 9.9, 11}, [4]float64{11.5, 12.1, 11.4, 12}, [4]float64{11.5, 13.1, 11.4, 13}), []Pattern{ThreeSoldiersCrows}, []float64{nan, nan, 0}},
+		{"inside and outside", candles([4]float64{10, 12, 8, 11}, [4]float64{10.5, 11, 9, 9.5}, [4]float64{9, 13, 7, 12}), []Pattern{InsideBar, OutsideBar}, []float64{nan, -1, 1}},
+		{"hammer", candles(append(decline, hammer)...), []Pattern{Hammer}, []float64{nan, nan, nan, 1}},
+		{"hanging man", candles(append(rise, hammer)...), []Pattern{Hammer}, []float64{nan, nan, nan, -1}},
+		{"inverted hammer", candles(append(decline, shootingStar)...), []Pattern{InvertedHammer}, []float64{nan, nan, nan, 1}},
+		{"shooting star", candles(append(rise, shootingStar)...), []Pattern{InvertedHammer}, []float64{nan, nan, nan, -1}},
+		{"doji after rise", candles(append(rise, [4]float64{10, 10.5, 9.5, 10.05})...), []Pattern{Doji}, []float64{nan, nan, nan, -1}},
+	}
+	for _, test := range tests {
+		got, err := Detect(test.bars, test.patterns...).WithTrendBars(2).Compute()
+		if err != nil {
+			t.Fatalf("%s: unexpected error occurred: %v ", test.name, err)
+		}
+		if err := signalsEqual(got, test.want); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	bars := candles([4]float64{10, 10.5, 8.5, 9}, [4]float64{8.8, 10.6, 8.7, 10.2})
+	if _, err := Detect(bars, Hammer).Compute(); err == nil {
+		t.Errorf("Want error for too few bars")
+	}
+	if _, err := Detect(bars, Pattern(99)).Compute(); err == nil {
+		t.Errorf("Want error for unknown pattern")
+	}
+	if _, err := Detect(bars).Compute(); err == nil {
+		t.Errorf("Want error without patterns")
+	}
+	bars.Open = nil
+	if _, err := Detect(bars, Engulfing).Compute(); err == nil {
+		t.Errorf("Want error without open")
+	}
+}
@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/marketdata"
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Data feeds offered by Alpaca. IEX is available on the free plan, SIP requires a subscription.
+const (
+	IEX = marketdata.IEX
+	SIP = marketdata.SIP
+)
+
+// Corporate action adjustments offered by Alpaca.
+const (
+	AdjustRaw      = marketdata.Raw
+	AdjustSplit    = marketdata.Split
+	AdjustDividend = marketdata.Dividend
+	AdjustAll      = marketdata.All
+)
+
+type AlpacaDataOpts struct {
+	ApiKey    string
+	ApiSecret string
+	// BaseURL of the market data API, defaults to https://data.alpaca.markets
+	BaseURL string
+	// Feed defaults to IEX
+	Feed marketdata.Feed
+	// Adjustment defaults to AdjustAll
+	Adjustment marketdata.Adjustment
+	// RequestLimit is the number of requests per minute, defaults to 200
+	RequestLimit int
+	// PageLimit is the number of bars per request, defaults to the API maximum
+	PageLimit int
+}
+
+type AlpacaProvider struct {
+	Client     *marketdata.Client
+	Limiter    *rate.Limiter
+	Feed       marketdata.Feed
+	Adjustment marketdata.Adjustment
+	PageLimit  int
+	location   *time.Location
+}
+
+// AlpacaData creates a data provider for daily bars from the Alpaca market data API.
+// Missing credentials are read from the APCA_API_KEY_ID and APCA_API_SECRET_KEY
+// environment variables.
+func AlpacaData(opts AlpacaDataOpts) *AlpacaProvider {
+
+	if opts.Feed == "" {
+		opts.Feed = IEX
+	}
+	if opts.Adjustment == "" {
+		opts.Adjustment = AdjustAll
+	}
+	if opts.RequestLimit <= 0 {
+		opts.RequestLimit = 200
+	}
+
+	log.Debug("Initializing Alpaca market data client", zap.String("feed", opts.Feed), zap.String("adjustment", string(opts.Adjustment)))
+	client := marketdata.NewClient(marketdata.ClientOpts{
+		APIKey:    opts.ApiKey,
+		APISecret: opts.ApiSecret,
+		BaseURL:   opts.BaseURL,
+		Feed:      opts.Feed,
+	})
+
+	location, err := time.LoadLocation(utils.US)
+	if err != nil {
+		log.Warn("Load exchange time zone", zap.Error(err))
+		location = time.UTC
+	}
+
+	log.Debug("Initializing rate limiter")
+	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(opts.RequestLimit)), 1)
+
+	return &AlpacaProvider{
+		Client:     client,
+		Limiter:    limiter,
+		Feed:       opts.Feed,
+		Adjustment: opts.Adjustment,
+		PageLimit:  opts.PageLimit,
+		location:   location,
+	}
+}
+
+// GetHistBars returns the last period daily bars of the symbol.
+func (alpaca *AlpacaProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("invalid period: %d", period)
+	}
+	// there are about 252 trading days per year, leave some room for holidays
+	end := time.Now()
+	start := end.AddDate(0, 0, -(period*365/252 + 10))
+
+	history, err := alpaca.GetHistBarsRange(symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if history.Len() < period {
+		return nil, fmt.Errorf("Not enough bars for %v", symbol)
+	}
+	res := history.Sub(history.Len()-period, history.Len())
+	return &res, nil
+}
+
+// GetHistBarsRange returns the daily bars of the symbol between start and end. Long
+// ranges are fetched page by page.
+func (alpaca *AlpacaProvider) GetHistBarsRange(symbol string, start, end time.Time) (*indicators.BarHistory, error) {
+
+	// Wait for a token from the rate limiter
+	if err := alpaca.Limiter.Wait(context.Background()); err != nil {
+		return nil, fmt.Errorf("Not allowed to proceed for symbol %s: %v\n", symbol, err)
+	}
+
+	log.Debug("Get historical bars", zap.String("symbol", symbol), zap.Time("start", start), zap.Time("end", end))
+	bars, err := alpaca.Client.GetBars(symbol, marketdata.GetBarsRequest{
+		TimeFrame:  marketdata.OneDay,
+		Adjustment: alpaca.Adjustment,
+		Start:      start,
+		End:        end,
+		PageLimit:  alpaca.PageLimit,
+		Feed:       alpaca.Feed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetHistBars: %w", err)
+	}
+
+	history := new(indicators.BarHistory)
+	for _, bar := range bars {
+		// daily bars start at midnight exchange time, keep the trading date only
+		year, month, day := bar.Timestamp.In(alpaca.location).Date()
+		history.Time = append(history.Time, time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+		history.Open = append(history.Open, bar.Open)
+		history.High = append(history.High, bar.High)
+		history.Low = append(history.Low, bar.Low)
+		history.Close = append(history.Close, bar.Close)
+		history.Volume = append(history.Volume, int64(bar.Volume))
+	}
+	return history, nil
+}
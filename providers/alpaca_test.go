@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// alpacaStandIn serves two pages of daily bars like the Alpaca data API.
+func alpacaStandIn(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/stocks/bars" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		*requests = append(*requests, q.Encode())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch q.Get("page_token") {
+		case "":
+			fmt.Fprint(w, `{"bars":{"AAPL":[
+				{"t":"2023-06-12T04:00:00Z","o":1,"h":2,"l":0.5,"c":1.5,"v":100},
+				{"t":"2023-06-13T04:00:00Z","o":2,"h":3,"l":1.5,"c":2.5,"v":200}
+			]},"next_page_token":"page2"}`)
+		case "page2":
+			fmt.Fprint(w, `{"bars":{"AAPL":[
+				{"t":"2023-06-14T04:00:00Z","o":3,"h":4,"l":2.5,"c":3.5,"v":300}
+			]},"next_page_token":null}`)
+		default:
+			t.Errorf("Unexpected page token: %s", q.Get("page_token"))
+		}
+	}))
+}
+
+func TestAlpacaProviderPagination(t *testing.T) {
+	var requests []string
+	server := alpacaStandIn(t, &requests)
+	defer server.Close()
+
+	provider := AlpacaData(AlpacaDataOpts{ApiKey: "key", ApiSecret: "secret", BaseURL: server.URL, Feed: SIP, Adjustment: AdjustSplit, PageLimit: 2})
+	start := day(12)
+	bars, err := provider.GetHistBarsRange("AAPL", start, start.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if bars.Len() != 3 || bars.Close[2] != 3.5 || bars.Volume[1] != 200 {
+		t.Fatalf("Unexpected bars: %+v", bars)
+	}
+	if want := day(14); !bars.Time[2].Equal(want) {
+		t.Errorf("Expected %v, got %v", want, bars.Time[2])
+	}
+}
+
+func TestAlpacaProviderOptions(t *testing.T) {
+	var requests []string
+	server := alpacaStandIn(t, &requests)
+	defer server.Close()
+
+	provider := AlpacaData(AlpacaDataOpts{BaseURL: server.URL, Feed: SIP, Adjustment: AdjustSplit, PageLimit: 2})
+	_, err := provider.GetHistBarsRange("AAPL", time.Now().AddDate(0, 0, -5), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	query, _ := url.ParseQuery(requests[0])
+	want := map[string]string{"feed": "sip", "adjustment": "split", "limit": "2", "timeframe": "1Day"}
+	for key, value := range want {
+		if query.Get(key) != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, query.Get(key))
+		}
+	}
+}
+
+func TestAlpacaProviderGetHistBars(t *testing.T) {
+	var requests []string
+	server := alpacaStandIn(t, &requests)
+	defer server.Close()
+
+	provider := AlpacaData(AlpacaDataOpts{BaseURL: server.URL})
+	bars, err := provider.GetHistBars("AAPL", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 2 || bars.Close[0] != 2.5 {
+		t.Errorf("Expected the last 2 bars, got %v", bars.Close)
+	}
+
+	if _, err = provider.GetHistBars("AAPL", 5); err == nil {
+		t.Errorf("Want error for not enough bars")
+	}
+}
@@ -0,0 +1,236 @@
+package providers
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/utils"
+	"go.uber.org/zap"
+)
+
+// cacheOverlap is the number of cached bars fetched again on an incremental update. The
+// most recent cached bar may have been stored before the session closed and is replaced,
+// the one before is final and is compared to detect adjustments of the history.
+const cacheOverlap = 2
+
+// cacheTolerance is the relative price difference above which a history is considered adjusted.
+const cacheTolerance = 1e-6
+
+// CachedProvider stores the bars of another provider on disk, one gob file per symbol, and
+// only fetches the days missing since the last call. If the overlapping bars reveal that the
+// history has been adjusted, e.g. for a split or dividend, the full history is fetched again.
+type CachedProvider struct {
+	provider DataProvider
+	dir      string
+	cal      *utils.TradingCalendar
+	location *time.Location
+	now      func() time.Time
+	locks    sync.Map
+}
+
+type cacheEntry struct {
+	Bars indicators.BarHistory
+}
+
+// Cache wraps the provider with a cache in the given directory. The calendar determines the
+// trading days for which bars are expected; without a calendar every weekday is a trading day.
+func Cache(provider DataProvider, dir string, cal *utils.TradingCalendar) (*CachedProvider, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	location, err := time.LoadLocation(utils.US)
+	if err != nil {
+		location = time.UTC
+	}
+	log.Debug("Initializing bar cache", zap.String("dir", dir))
+	return &CachedProvider{
+		provider: provider,
+		dir:      dir,
+		cal:      cal,
+		location: location,
+		now:      time.Now,
+	}, nil
+}
+
+// GetHistBars returns the last period bars of the symbol, fetching missing bars if necessary.
+func (c *CachedProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	lock, _ := c.locks.LoadOrStore(symbol, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	cached, err := c.load(symbol)
+	if err != nil {
+		log.Warn("Read cache", zap.String("symbol", symbol), zap.Error(err))
+		cached = nil
+	}
+
+	var bars *indicators.BarHistory
+	switch {
+	case cached == nil || cached.Len() < period || cached.Len() < cacheOverlap:
+		bars, err = c.fetch(symbol, period)
+	default:
+		bars, err = c.update(symbol, cached, period)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bars.Len() < period {
+		return nil, fmt.Errorf("Not enough bars for %v", symbol)
+	}
+	res := bars.Sub(bars.Len()-period, bars.Len())
+	return &res, nil
+}
+
+// Invalidate removes the cached bars of the symbol.
+func (c *CachedProvider) Invalidate(symbol string) error {
+	err := os.Remove(c.path(symbol))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// update appends the bars missing since the last cached bar.
+func (c *CachedProvider) update(symbol string, cached *indicators.BarHistory, period int) (*indicators.BarHistory, error) {
+	last := cached.Time[cached.Len()-1]
+	missing := c.tradingDaysAfter(last)
+	if missing == 0 {
+		log.Debug("Serve bars from cache", zap.String("symbol", symbol))
+		return cached, nil
+	}
+
+	log.Debug("Update cached bars", zap.String("symbol", symbol), zap.Int("missing", missing))
+	fresh, err := c.provider.GetHistBars(symbol, missing+cacheOverlap)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh.HasTime() {
+		return nil, fmt.Errorf("cannot cache bars without timestamps for %v", symbol)
+	}
+
+	// compare the final overlapping bar
+	check := cached.Len() - cacheOverlap
+	idx := fresh.IndexOf(cached.Time[check])
+	if idx < 0 || !samePrice(fresh.Close[idx], cached.Close[check]) {
+		log.Info("History adjusted, fetch all bars", zap.String("symbol", symbol))
+		return c.fetch(symbol, maxInt(period, cached.Len()))
+	}
+
+	merged := cached.Sub(0, check)
+	merged = appendBars(merged, fresh.Sub(idx, fresh.Len()))
+	if err := c.store(symbol, &merged); err != nil {
+		log.Warn("Write cache", zap.String("symbol", symbol), zap.Error(err))
+	}
+	return &merged, nil
+}
+
+// fetch replaces the cached bars by the last period bars of the provider.
+func (c *CachedProvider) fetch(symbol string, period int) (*indicators.BarHistory, error) {
+	log.Debug("Fetch bars", zap.String("symbol", symbol), zap.Int("period", period))
+	bars, err := c.provider.GetHistBars(symbol, period)
+	if err != nil {
+		return nil, err
+	}
+	if !bars.HasTime() {
+		return nil, fmt.Errorf("cannot cache bars without timestamps for %v", symbol)
+	}
+	if err := c.store(symbol, bars); err != nil {
+		log.Warn("Write cache", zap.String("symbol", symbol), zap.Error(err))
+	}
+	return bars, nil
+}
+
+// tradingDaysAfter counts the trading days after the given date up to and including today.
+func (c *CachedProvider) tradingDaysAfter(last time.Time) int {
+	year, month, day := c.now().In(c.location).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = last.Date()
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	n := 0
+	for ; !t.After(today); t = t.AddDate(0, 0, 1) {
+		if c.isTradingDay(t) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *CachedProvider) isTradingDay(t time.Time) bool {
+	if c.cal != nil {
+		return c.cal.IsTradingDay(t)
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+func (c *CachedProvider) path(symbol string) string {
+	return filepath.Join(c.dir, symbol+".gob")
+}
+
+func (c *CachedProvider) load(symbol string) (*indicators.BarHistory, error) {
+	f, err := os.Open(c.path(symbol))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, err
+	}
+	if !entry.Bars.HasTime() {
+		return nil, fmt.Errorf("cached bars without timestamps")
+	}
+	return &entry.Bars, nil
+}
+
+// store writes the bars to a temporary file first, so that readers never see a partial file.
+func (c *CachedProvider) store(symbol string, bars *indicators.BarHistory) error {
+	tmp, err := os.CreateTemp(c.dir, symbol+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(cacheEntry{Bars: *bars}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(symbol))
+}
+
+func samePrice(a, b float64) bool {
+	return math.Abs(a-b) <= cacheTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func appendBars(bars indicators.BarHistory, other indicators.BarHistory) indicators.BarHistory {
+	return indicators.BarHistory{
+		Time:   append(append([]time.Time{}, bars.Time...), other.Time...),
+		Open:   append(append([]float64{}, bars.Open...), other.Open...),
+		High:   append(append([]float64{}, bars.High...), other.High...),
+		Low:    append(append([]float64{}, bars.Low...), other.Low...),
+		Close:  append(append([]float64{}, bars.Close...), other.Close...),
+		Volume: append(append([]int64{}, bars.Volume...), other.Volume...),
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
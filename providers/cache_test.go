@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+)
+
+// countingProvider serves the bars up to today and records the requested periods.
+type countingProvider struct {
+	bars     *indicators.BarHistory
+	today    time.Time
+	requests []int
+}
+
+func (p *countingProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	p.requests = append(p.requests, period)
+	end := p.bars.IndexOf(p.today) + 1
+	if end < period {
+		return nil, fmt.Errorf("Not enough bars for %v", symbol)
+	}
+	res := p.bars.Sub(end-period, end)
+	return &res, nil
+}
+
+func newTestCache(t *testing.T, inner *countingProvider) *CachedProvider {
+	cache, err := Cache(inner, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	cache.now = func() time.Time { return inner.today.Add(20 * time.Hour) }
+	return cache
+}
+
+func TestCacheServesFromDisk(t *testing.T) {
+	inner := &countingProvider{bars: weekdayBars(), today: day(14)}
+	cache := newTestCache(t, inner)
+
+	for i := 0; i < 3; i++ {
+		bars, err := cache.GetHistBars("AAPL", 5)
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if bars.Len() != 5 || bars.Close[4] != 14 {
+			t.Fatalf("Unexpected bars: %v", bars.Close)
+		}
+	}
+	if len(inner.requests) != 1 {
+		t.Errorf("Expected 1 request, got %v", inner.requests)
+	}
+
+	// a shorter history is served from the cache as well
+	bars, _ := cache.GetHistBars("AAPL", 2)
+	if bars.Len() != 2 || len(inner.requests) != 1 {
+		t.Errorf("Expected cached bars, got %v after %v", bars.Close, inner.requests)
+	}
+}
+
+func TestCacheFetchesMissingDays(t *testing.T) {
+	inner := &countingProvider{bars: weekdayBars(), today: day(14)}
+	cache := newTestCache(t, inner)
+	_, _ = cache.GetHistBars("AAPL", 5)
+
+	// Thursday, Friday and Monday are missing
+	inner.today = day(19)
+	bars, err := cache.GetHistBars("AAPL", 5)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if len(inner.requests) != 2 || inner.requests[1] != 3+cacheOverlap {
+		t.Errorf("Expected incremental request of %d bars, got %v", 3+cacheOverlap, inner.requests)
+	}
+	_, err = sliceEqual(bars.Close, []float64{13, 14, 15, 16, 19})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheRefetchesAdjustedHistory(t *testing.T) {
+	inner := &countingProvider{bars: weekdayBars(), today: day(14)}
+	cache := newTestCache(t, inner)
+	_, _ = cache.GetHistBars("AAPL", 5)
+
+	// 2:1 split on June 15th
+	for i := range inner.bars.Close {
+		if inner.bars.Time[i].Day() < 15 {
+			inner.bars.Close[i] /= 2
+		}
+	}
+	inner.today = day(15)
+	bars, err := cache.GetHistBars("AAPL", 5)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if len(inner.requests) != 3 || inner.requests[2] != 5 {
+		t.Errorf("Expected full request after adjustment, got %v", inner.requests)
+	}
+	_, err = sliceEqual(bars.Close, []float64{4.5, 6, 6.5, 7, 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	inner := &countingProvider{bars: weekdayBars(), today: day(14)}
+	cache := newTestCache(t, inner)
+	_, _ = cache.GetHistBars("AAPL", 5)
+
+	if err := cache.Invalidate("AAPL"); err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	_, _ = cache.GetHistBars("AAPL", 5)
+	if len(inner.requests) != 2 {
+		t.Errorf("Expected 2 requests, got %v", inner.requests)
+	}
+	if err := cache.Invalidate("MSFT"); err != nil {
+		t.Errorf("Unexpected error for unknown symbol: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package providers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+	"go.uber.org/zap"
+)
+
+// CSVLayout maps the columns of a CSV file to the bar fields. Column names are matched
+// case-insensitively and surrounding spaces and angle brackets are ignored, so "<CLOSE>"
+// matches "close". An empty Volume column means the file has no volume and leaves the
+// Volume of the history empty. DateFormat is a time layout and defaults to "2006-01-02".
+type CSVLayout struct {
+	Date       string
+	Open       string
+	High       string
+	Low        string
+	Close      string
+	Volume     string
+	DateFormat string
+	Comma      rune
+}
+
+var (
+	// YahooLayout reads the CSV export of Yahoo Finance.
+	YahooLayout = CSVLayout{Date: "Date", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume", DateFormat: "2006-01-02"}
+	// StooqLayout reads the daily CSV download of stooq.com.
+	StooqLayout = CSVLayout{Date: "Date", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume", DateFormat: "2006-01-02"}
+	// StooqBulkLayout reads the files of the stooq.com bulk download.
+	StooqBulkLayout = CSVLayout{Date: "DATE", Open: "OPEN", High: "HIGH", Low: "LOW", Close: "CLOSE", Volume: "VOL", DateFormat: "20060102"}
+	// FmpLayout reads the CSV export of the FMP historical price endpoint.
+	FmpLayout = CSVLayout{Date: "date", Open: "open", High: "high", Low: "low", Close: "close", Volume: "volume", DateFormat: "2006-01-02"}
+)
+
+type CSVProvider struct {
+	Dir string
+	// Pattern is the file name of a symbol with %s standing for the symbol, defaults to "%s.csv"
+	Pattern string
+	Layout  CSVLayout
+}
+
+// CSV creates a data provider reading one CSV file per symbol from the given directory.
+func CSV(dir string, layout CSVLayout) *CSVProvider {
+	log.Debug("Initializing CSV provider", zap.String("dir", dir))
+	return &CSVProvider{Dir: dir, Pattern: "%s.csv", Layout: layout}
+}
+
+// GetHistBars returns the last period bars of the symbol.
+func (p *CSVProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	history, err := p.Load(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if history.Len() < period {
+		return nil, fmt.Errorf("Not enough bars for %v", symbol)
+	}
+	res := history.Sub(history.Len()-period, history.Len())
+	return &res, nil
+}
+
+// Load returns all bars of the symbol in chronological order.
+func (p *CSVProvider) Load(symbol string) (*indicators.BarHistory, error) {
+	path := filepath.Join(p.Dir, fmt.Sprintf(p.Pattern, symbol))
+	log.Debug("Get historical bars", zap.String("symbol", symbol), zap.String("file", path))
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	history, err := ReadCSV(f, p.Layout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return history, nil
+}
+
+// ReadCSV reads bars in the given layout. Rows with missing values, e.g. "null" in
+// Yahoo exports, are skipped. The bars are returned in chronological order regardless
+// of the order in the file.
+func ReadCSV(r io.Reader, layout CSVLayout) (*indicators.BarHistory, error) {
+	reader := csv.NewReader(r)
+	if layout.Comma != 0 {
+		reader.Comma = layout.Comma
+	}
+	reader.TrimLeadingSpace = true
+	if layout.DateFormat == "" {
+		layout.DateFormat = "2006-01-02"
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[normalizeColumn(name)] = i
+	}
+	index := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := columns[normalizeColumn(name)]
+		if !ok {
+			return -1, fmt.Errorf("missing column %q", name)
+		}
+		return i, nil
+	}
+
+	var idx [6]int
+	for i, name := range []string{layout.Date, layout.Open, layout.High, layout.Low, layout.Close, layout.Volume} {
+		if idx[i], err = index(name); err != nil {
+			return nil, err
+		}
+	}
+	if idx[0] < 0 || idx[4] < 0 {
+		return nil, errors.New("layout requires a date and a close column")
+	}
+
+	var rows []csvRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row, ok, err := parseRow(record, idx, layout.DateFormat)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].time.Before(rows[j].time) })
+
+	history := new(indicators.BarHistory)
+	for _, row := range rows {
+		history.Time = append(history.Time, row.time)
+		history.Open = append(history.Open, row.open)
+		history.High = append(history.High, row.high)
+		history.Low = append(history.Low, row.low)
+		history.Close = append(history.Close, row.close)
+		if idx[5] >= 0 {
+			history.Volume = append(history.Volume, row.volume)
+		}
+	}
+	return history, nil
+}
+
+type csvRow struct {
+	time                   time.Time
+	open, high, low, close float64
+	volume                 int64
+}
+
+// parseRow converts a record into a row. It reports false for rows with missing values.
+func parseRow(record []string, idx [6]int, dateFormat string) (csvRow, bool, error) {
+	var row csvRow
+	values := make([]float64, 5)
+	for i := 1; i < len(idx); i++ {
+		if idx[i] < 0 {
+			continue
+		}
+		if idx[i] >= len(record) {
+			return row, false, fmt.Errorf("missing field %d", idx[i])
+		}
+		field := strings.TrimSpace(record[idx[i]])
+		if field == "" || strings.EqualFold(field, "null") {
+			return row, false, nil
+		}
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return row, false, err
+		}
+		values[i-1] = v
+	}
+
+	if idx[0] >= len(record) {
+		return row, false, fmt.Errorf("missing field %d", idx[0])
+	}
+	t, err := time.Parse(dateFormat, strings.TrimSpace(record[idx[0]]))
+	if err != nil {
+		return row, false, err
+	}
+	row.time = t
+	row.close = values[3]
+	// missing price columns default to the close
+	row.open, row.high, row.low = row.close, row.close, row.close
+	if idx[1] >= 0 {
+		row.open = values[0]
+	}
+	if idx[2] >= 0 {
+		row.high = values[1]
+	}
+	if idx[3] >= 0 {
+		row.low = values[2]
+	}
+	row.volume = int64(values[4])
+	return row, true, nil
+}
+
+func normalizeColumn(name string) string {
+	name = strings.TrimPrefix(name, "\ufeff")
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "<>"))
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/d1l1x/gofin/indicators"
+)
+
+const yahooCSV = `Date,Open,High,Low,Close,Adj Close,Volume
+2023-06-12,181.27,183.89,180.97,183.79,183.09,54755000
+2023-06-13,182.80,184.15,182.44,183.31,182.61,54929100
+2023-06-14,183.37,184.39,182.02,183.95,183.25,57462900
+2023-06-15,183.96,186.52,183.78,186.01,185.30,65433200
+`
+
+const stooqBulkCSV = `<TICKER>,<PER>,<DATE>,<TIME>,<OPEN>,<HIGH>,<LOW>,<CLOSE>,<VOL>,<OPENINT>
+AAPL.US,D,20230612,000000,181.27,183.89,180.97,183.79,54755000,0
+AAPL.US,D,20230613,000000,182.80,184.15,182.44,183.31,54929100,0
+`
+
+const fmpCSV = `date,open,high,low,close,adjClose,volume,unadjustedVolume,change,changePercent,vwap,label,changeOverTime
+2023-06-13,182.8,184.15,182.44,183.31,183.31,54929100,54929100,0.51,0.279,183.3,"June 13, 23",0.00279
+2023-06-12,181.27,183.89,180.97,183.79,183.79,54755000,54755000,2.52,1.39,182.88,"June 12, 23",0.0139
+`
+
+func TestReadCSVYahoo(t *testing.T) {
+	bars, err := ReadCSV(strings.NewReader(yahooCSV), YahooLayout)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 4 || bars.Close[3] != 186.01 || bars.Volume[0] != 54755000 {
+		t.Fatalf("Unexpected bars: %+v", bars)
+	}
+	if want := day(15); !bars.Time[3].Equal(want) {
+		t.Errorf("Expected %v, got %v", want, bars.Time[3])
+	}
+}
+
+func TestReadCSVStooqBulk(t *testing.T) {
+	bars, err := ReadCSV(strings.NewReader(stooqBulkCSV), StooqBulkLayout)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 2 || bars.High[1] != 184.15 || bars.Time[1].Day() != 13 {
+		t.Errorf("Unexpected bars: %+v", bars)
+	}
+}
+
+func TestReadCSVFmpIsSorted(t *testing.T) {
+	bars, err := ReadCSV(strings.NewReader(fmpCSV), FmpLayout)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 2 || bars.Time[0].Day() != 12 || bars.Close[1] != 183.31 {
+		t.Errorf("Expected chronological order, got %+v", bars)
+	}
+}
+
+func TestReadCSVCustomLayout(t *testing.T) {
+	data := "day;price\n12.06.2023;10.5\n13.06.2023;null\n14.06.2023;11\n"
+	layout := CSVLayout{Date: "day", Close: "price", DateFormat: "02.01.2006", Comma: ';'}
+
+	bars, err := ReadCSV(strings.NewReader(data), layout)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 2 || bars.Open[1] != 11 || bars.Volume != nil {
+		t.Errorf("Unexpected bars: %+v", bars)
+	}
+	if _, err := indicators.OBV(*bars).Compute(); err == nil {
+		t.Errorf("Want error of volume indicator without volume")
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	if _, err := ReadCSV(strings.NewReader(yahooCSV), CSVLayout{Date: "Date", Close: "Last", DateFormat: "2006-01-02"}); err == nil {
+		t.Errorf("Want error for missing column")
+	}
+	if _, err := ReadCSV(strings.NewReader(yahooCSV), CSVLayout{Date: "Date", Close: "Close", DateFormat: "20060102"}); err == nil {
+		t.Errorf("Want error for invalid date")
+	}
+	if _, err := ReadCSV(strings.NewReader(""), YahooLayout); err == nil {
+		t.Errorf("Want error for empty file")
+	}
+}
+
+func TestCSVProviderGetHistBars(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "AAPL.csv"), []byte(yahooCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	provider := CSV(dir, YahooLayout)
+
+	bars, err := provider.GetHistBars("AAPL", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 2 || bars.Close[0] != 183.95 {
+		t.Errorf("Expected the last 2 bars, got %v", bars.Close)
+	}
+	if _, err = provider.GetHistBars("AAPL", 10); err == nil {
+		t.Errorf("Want error for not enough bars")
+	}
+	if _, err = provider.GetHistBars("MSFT", 1); err == nil {
+		t.Errorf("Want error for missing file")
+	}
+}
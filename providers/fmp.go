@@ -11,6 +11,8 @@ import (
 	"time"
 )
 
+const fmpDateLayout = "2006-01-02"
+
 type FmpProvider struct {
 	Client  *fmp.APIClient
 	Limiter *rate.Limiter
@@ -55,7 +57,14 @@ func (fmp *FmpProvider) GetHistBars(symbol string, period int) (*indicators.BarH
 	if len(bars.Historical) >= period {
 		//log.Debug("Got bars: %v [%v, ...]", symbol, bars.Historical[0].AdjClose)
 		history := new(indicators.BarHistory)
-		for _, bar := range bars.Historical {
+		// FMP returns the most recent bar first
+		for i := len(bars.Historical) - 1; i >= 0; i-- {
+			bar := bars.Historical[i]
+			date, err := time.Parse(fmpDateLayout, bar.Date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date for %v: %w", symbol, err)
+			}
+			history.Time = append(history.Time, date)
 			history.Open = append(history.Open, bar.Open)
 			history.High = append(history.High, bar.High)
 			history.Low = append(history.Low, bar.Low)
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fmp "github.com/spacecodewor/fmpcloud-go"
+	"golang.org/x/time/rate"
+)
+
+func TestFmpBarsInChronologicalOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/historical-price-full/AAPL" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// FMP returns the most recent bar first
+		fmt.Fprint(w, `{"symbol":"AAPL","historical":[
+			{"date":"2023-06-14","open":3,"high":4,"low":2.5,"close":3.5,"volume":300},
+			{"date":"2023-06-13","open":2,"high":3,"low":1.5,"close":2.5,"volume":200},
+			{"date":"2023-06-12","open":1,"high":2,"low":0.5,"close":1.5,"volume":100}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := fmp.NewAPIClient(fmp.Config{APIKey: "test", APIUrl: fmp.APIUrl(server.URL)})
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	provider := &FmpProvider{Client: client, Limiter: rate.NewLimiter(rate.Inf, 1)}
+
+	bars, err := provider.GetHistBars("AAPL", 3)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if bars.Len() != 3 || bars.Time[0].Day() != 12 || bars.Time[2].Day() != 14 {
+		t.Fatalf("Expected bars from June 12 to 14, got %v", bars.Time)
+	}
+	if bars.Open[0] != 1 || bars.High[1] != 3 || bars.Low[2] != 2.5 || bars.Close[2] != 3.5 || bars.Volume[0] != 100 {
+		t.Errorf("Unexpected bars: %+v", bars)
+	}
+
+	if _, err := provider.GetHistBars("AAPL", 4); err == nil {
+		t.Errorf("Want error for too few bars")
+	}
+}
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d1l1x/gofin/indicators"
+)
+
+// day returns the given day of June 2023.
+func day(d int) time.Time {
+	return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+// weekdayBars returns bars for all weekdays of June 2023 with the day of month as price.
+func weekdayBars() *indicators.BarHistory {
+	bars := &indicators.BarHistory{}
+	for d := 1; d <= 30; d++ {
+		t := day(d)
+		if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+			continue
+		}
+		p := float64(d)
+		bars.Time = append(bars.Time, t)
+		bars.Open = append(bars.Open, p)
+		bars.High = append(bars.High, p)
+		bars.Low = append(bars.Low, p)
+		bars.Close = append(bars.Close, p)
+		bars.Volume = append(bars.Volume, int64(d))
+	}
+	return bars
+}
+
+func sliceEqual(a, b []float64) (bool, error) {
+	if len(a) != len(b) {
+		return false, fmt.Errorf("slices must have equal length: %d != %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false, fmt.Errorf("%v!=%v at index %d", a[i], b[i], i)
+		}
+	}
+	return true, nil
+}
@@ -1,34 +1,64 @@
 package gofin
 
 import (
+	"fmt"
 	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
 	"github.com/d1l1x/gofin/providers"
 	"github.com/d1l1x/gofin/utils"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"math"
 	"time"
 	"context"
 )
 
 var log = utils.NewZapLogger("TradingSystem", utils.Debug) //.Sugar()
 
+const orderPollInterval = 100 * time.Millisecond
+
 type TradingSystem struct {
 	watchlist *utils.Watchlist
-	broker    *brokers.AlpacaBroker
+	broker    brokers.Broker
 	cal       *utils.TradingCalendar
 	provider  providers.DataProvider
-	// mm 	 MoneyManager
+	mm        MoneyManager
 	maxPositions int
+	lookback     int
+	exits        []ExitRule
+	positions    map[string]*PositionState
+	// exited holds the symbols closed by the last CheckOpenPositions, they are not bought
+	// back on the same bar.
+	exited map[string]bool
 }
 
-func NewTradingSystem(broker *brokers.AlpacaBroker, watchlist *utils.Watchlist, cal *utils.TradingCalendar, provider providers.DataProvider) *TradingSystem {
+func NewTradingSystem(broker brokers.Broker, watchlist *utils.Watchlist, cal *utils.TradingCalendar, provider providers.DataProvider) *TradingSystem {
 	return &TradingSystem{
 		watchlist: watchlist,
 		broker:    broker,
 		cal:       cal,
 		provider:  provider,
+		mm:        EqualWeight{},
+		lookback:  100,
 	}
 }
 
+// SetMoneyManager sets the position sizing. Defaults to EqualWeight.
+func (ts *TradingSystem) SetMoneyManager(mm MoneyManager) {
+	ts.mm = mm
+}
+
+// AddExitRule adds a rule for closing open positions. Positions are closed as soon as
+// any of the rules triggers.
+func (ts *TradingSystem) AddExitRule(rule ExitRule) {
+	ts.exits = append(ts.exits, rule)
+}
+
+// SetMaxPositions sets the maximum number of positions held at the same time.
+func (ts *TradingSystem) SetMaxPositions(n int) {
+	ts.maxPositions = n
+}
+
 func (ts *TradingSystem) Init() {
 
 	// TODO: Add error checking if necessary objects are initialized
@@ -41,42 +71,123 @@ func (ts *TradingSystem) Init() {
 	log.Info("", zap.Float64("Buying power", bp))
 }
 
+// CheckOpenPositions applies the exit rules to all open positions and places market orders
+// closing the positions for which a rule triggers. It returns the number of positions that
+// remain open. If the open positions cannot be fetched, the tracked positions are kept and
+// their number is returned.
 func (ts *TradingSystem) CheckOpenPositions() int {
 
 	log.Info("Get open positions")
+	ts.exited = make(map[string]bool)
 	positions, err := ts.broker.GetOpenPositions()
 	if err != nil {
 		log.Error("Get open positions", zap.Error(err))
+		return len(ts.positions)
+	}
+
+	// keep track of new positions and forget about closed ones
+	open := make(map[string]*PositionState, len(positions))
+	for _, p := range positions {
+		state, ok := ts.positions[p.Symbol]
+		if !ok {
+			state = newPositionState(p)
+			ts.resolveEntryTime(state)
+		}
+		state.Position = p
+		open[p.Symbol] = state
+	}
+	ts.positions = open
+
+	if len(ts.exits) == 0 {
+		return len(positions)
 	}
 
 	errPos := make(chan error, len(positions))
+	closed := make(chan string, len(positions))
 	for _, pos := range positions {
-		go func(p brokers.Position) {
-			log.Info("Check stopp loss", zap.String("Symbol", p.Symbol))
-			// err := ts.broker.GetHistBars(a.Symbol, 100)
-			//if err != nil {
-			//	errChan <- err
-			//	return
-			//}
-
-			log.Info("Check take profit", zap.String("Symbol", p.Symbol))
+		go func(state *PositionState) {
+			bars, err := ts.provider.GetHistBars(state.Symbol, ts.lookback)
+			if err != nil {
+				errPos <- err
+				closed <- ""
+				return
+			}
+			state.update(bars)
 
-			log.Info("Check time based exits", zap.String("Symbol", p.Symbol)
+			exit, err := ts.checkExitRules(state, bars)
+			if err != nil || !exit {
+				errPos <- err
+				closed <- ""
+				return
+			}
 
-			errPos <- nil
-		}(pos)
+			side := brokers.Sell
+			if !state.IsLong() {
+				side = brokers.Buy
+			}
+			_, err = ts.broker.PlaceOrder(brokers.Order{
+				Symbol:      state.Symbol,
+				Side:        side,
+				Type:        brokers.Market,
+				TimeInForce: brokers.Day,
+				Qty:         decimal.NewFromFloat(math.Abs(state.Quantity)),
+			})
+			errPos <- err
+			if err != nil {
+				closed <- ""
+				return
+			}
+			closed <- state.Symbol
+		}(open[pos.Symbol])
 	}
 
 	// Check possible channel errors
-	for range ts.watchlist.Assets {
+	openPositions := len(positions)
+	for range positions {
 		if err := <-errPos; err != nil {
 			log.Error("Channel error", zap.Error(err))
 		}
+		if symbol := <-closed; symbol != "" {
+			delete(ts.positions, symbol)
+			ts.exited[symbol] = true
+			openPositions--
+		}
 	}
 
 	return openPositions
 }
 
+// resolveEntryTime looks up the entry time of a new position whose broker does not report
+// it with the position. The result is kept in the position state for later checks.
+func (ts *TradingSystem) resolveEntryTime(state *PositionState) {
+	timer, ok := ts.broker.(brokers.PositionTimer)
+	if !ok || !state.OpenedAt.IsZero() {
+		return
+	}
+	t, err := timer.PositionOpenedAt(state.Position)
+	if err != nil {
+		log.Warn("Unknown entry time of position", zap.String("Symbol", state.Symbol), zap.Error(err))
+		return
+	}
+	state.EntryTime = t
+}
+
+// checkExitRules returns true if any of the exit rules triggers for the position.
+func (ts *TradingSystem) checkExitRules(state *PositionState, bars *indicators.BarHistory) (bool, error) {
+	for _, rule := range ts.exits {
+		log.Info("Check exit", zap.String("Symbol", state.Symbol), zap.String("Rule", rule.Name()))
+		exit, err := rule.Exit(state, bars)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", rule.Name(), err)
+		}
+		if exit {
+			log.Info("Exit triggered", zap.String("Symbol", state.Symbol), zap.String("Rule", rule.Name()), zap.Time("Entry", state.EntryTime))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (ts *TradingSystem) WaitForTradingHours() {
 
 	if !ts.cal.IsTradingDay(time.Now()) {
@@ -111,21 +222,27 @@ func (ts *TradingSystem) processOrders(orders *[]brokers.Order, timeout time.Dur
 				stop, _ := order.StopPrice.Float64()
 				log.Info("Place order", zap.String("Symbol", order.Symbol), zap.Any("side", order.Side), zap.Float64("qty", qty), zap.Float64("limit", limit), zap.Float64("stop", stop))
 				//TODO: Account for different order types
-				orderID, err := sendOrderToBroker(order)
+				orderID, err := ts.broker.PlaceOrder(order)
 				if err != nil {
 					errChan <- err
 					return
 				}
 				// wait for order to be filled
 				for {
-					orderId, err := ts.broker.GetOrder(orderID)
+					status, err := ts.broker.GetOrder(orderID)
 					if err != nil {
 						errChan <- err
 						return
 					}
-					if orderId.Status == "filled" {
+					if status.Status == brokers.Filled {
+						order = *status
 						break
 					}
+					if !status.Status.IsOpen() {
+						errChan <- fmt.Errorf("order %s for %s %s", orderID, order.Symbol, status.Status)
+						return
+					}
+					time.Sleep(orderPollInterval)
 				}
 				filled <- &order
 			}(order)
@@ -137,8 +254,7 @@ func (ts *TradingSystem) processOrders(orders *[]brokers.Order, timeout time.Dur
 	go func() {
 		for i := 0; i < len(*orders); i++ {
 			select {
-			case <-filled:
-				order := <-filled
+			case order := <-filled:
 				price, _ := order.FilledAvgPrice.Float64()
 				qty, _ := order.FilledQty.Float64()
 				log.Info("Order filled", zap.String("Symbol", order.Symbol), zap.Float64("qty", qty), zap.Float64("FillPrice", price))
@@ -179,53 +295,154 @@ func (ts *TradingSystem) Run() {
 			continue
 		}
 
-		// Check assets for filter criteria
-		errChan := make(chan error, len(ts.watchlist.Assets))
+		assetsToConsider, history := ts.selectAssets()
 
-		var assetsToConsider []utils.Asset
+		log.Info("Assets to consider", zap.Int("Number of assets", len(assetsToConsider)))
 
-		////TODO: Check setup
+		listOfOrders := ts.prepareOrders(assetsToConsider, history, openPositions)
 
-		// filter and rank all assets
-		for i, asset := range ts.watchlist.Assets {
-			go func(idx int, a utils.Asset) {
-				bars, err := ts.provider.GetHistBars(a.Symbol, 100)
-				if err != nil {
-					errChan <- err
-					return
-				}
-				passed := ts.watchlist.ApplyFilters(a.Symbol, bars)
+		timeout := ts.cal.OnClose.End.Sub(time.Now())
+		filledOrders, cancelledOrders := ts.processOrders(&listOfOrders, timeout)
 
-				ts.watchlist.ApplyRanking(&ts.watchlist.Assets[idx], bars)
-				if passed {
-					assetsToConsider = append(assetsToConsider, ts.watchlist.Assets[idx])
-				}
-				errChan <- nil
-			}(i, asset)
-		}
-		// Check possible channel errors
-		for range ts.watchlist.Assets {
-			if err := <-errChan; err != nil {
-				log.Error("Channel error", zap.Error(err))
+		//if all orders have been filled for today, wait for next trading day
+		log.Info("Orders filled. Waiting for next trading day at close.", zap.Int("Filled orders", filledOrders), zap.Int("Cancelled orders", cancelledOrders))
+		time.Sleep(time.Until(ts.cal.NextDayOnClose(time.Now()).Start))
+	}
+}
+
+// selectAssets fetches the bar history of every watchlist asset and returns the assets passing
+// all filters, ranked according to the watchlist ranking, together with the fetched bars by symbol.
+func (ts *TradingSystem) selectAssets() ([]utils.Asset, map[string]*indicators.BarHistory) {
+
+	// Check assets for filter criteria
+	errChan := make(chan error, len(ts.watchlist.Assets))
+	history := make([]*indicators.BarHistory, len(ts.watchlist.Assets))
+
+	////TODO: Check setup
+
+	// fetch bars of all assets
+	for i, asset := range ts.watchlist.Assets {
+		go func(idx int, a utils.Asset) {
+			bars, err := ts.provider.GetHistBars(a.Symbol, ts.lookback)
+			if err != nil {
+				errChan <- err
+				return
 			}
+			history[idx] = bars
+			errChan <- nil
+		}(i, asset)
+	}
+	// Check possible channel errors
+	for range ts.watchlist.Assets {
+		if err := <-errChan; err != nil {
+			log.Error("Channel error", zap.Error(err))
 		}
+	}
 
-		log.Info("Rank assets")
-		ts.watchlist.RankAssets(assetsToConsider)
+	// filter and rank all assets. Indicators are shared between assets, hence
+	// this is done sequentially.
+	var assetsToConsider []utils.Asset
+	bySymbol := make(map[string]*indicators.BarHistory)
+	for i := range ts.watchlist.Assets {
+		bars := history[i]
+		if bars == nil {
+			continue
+		}
+		bySymbol[ts.watchlist.Assets[i].Symbol] = bars
+		passed := ts.watchlist.ApplyFilters(ts.watchlist.Assets[i].Symbol, bars)
 
-		log.Info("Assets to consider", zap.Int("Number of assets", len(assetsToConsider)))
+		ts.watchlist.ApplyRanking(&ts.watchlist.Assets[i], bars)
+		if passed {
+			assetsToConsider = append(assetsToConsider, ts.watchlist.Assets[i])
+		}
+	}
 
-		log.Info("Prepare orders")
+	log.Info("Rank assets")
+	ts.watchlist.RankAssets(assetsToConsider)
 
-		log.Info("Check money management")
+	return assetsToConsider, bySymbol
+}
 
-		var listOfOrders []brokers.Order
+// prepareOrders turns the ranked assets into market orders for the free position slots.
+// Assets that are already held or were closed by the last CheckOpenPositions are skipped, the position sizes are determined by the money
+// manager and limited by the buying power.
+func (ts *TradingSystem) prepareOrders(assets []utils.Asset, history map[string]*indicators.BarHistory, openPositions int) []brokers.Order {
 
-		timeout := ts.cal.OnClose.End.Sub(time.Now())
-		filledOrders, cancelledOrders := ts.processOrders(&listOfOrders, timeout)
+	log.Info("Prepare orders")
 
-		//if all orders have been filled for today, wait for next trading day
-		log.Info("Orders filled. Waiting for next trading day at close.", zap.Int("Filled orders", filledOrders), zap.Int("Cancelled orders", cancelledOrders))
-		time.Sleep(time.Until(ts.cal.NextDayOnClose(time.Now()).Start))
+	var listOfOrders []brokers.Order
+
+	slots := ts.maxPositions - openPositions
+	if slots <= 0 || len(assets) == 0 {
+		return listOfOrders
+	}
+
+	held := make(map[string]bool)
+	positions, err := ts.broker.GetOpenPositions()
+	if err != nil {
+		log.Error("Get open positions", zap.Error(err))
+		return listOfOrders
 	}
+	for _, p := range positions {
+		held[p.Symbol] = true
+	}
+	for symbol := range ts.exited {
+		held[symbol] = true
+	}
+
+	log.Info("Check money management")
+
+	bp, err := ts.broker.BuyingPower()
+	if err != nil {
+		log.Error("Get buying power", zap.Error(err))
+		return listOfOrders
+	}
+	equity, err := ts.broker.Equity()
+	if err != nil {
+		log.Error("Get equity", zap.Error(err))
+		return listOfOrders
+	}
+	account := Account{
+		Equity:       equity,
+		BuyingPower:  bp,
+		MaxPositions: ts.maxPositions,
+		OpenSlots:    slots,
+	}
+
+	for _, asset := range assets {
+		if len(listOfOrders) >= slots {
+			break
+		}
+		if held[asset.Symbol] {
+			continue
+		}
+		bars, ok := history[asset.Symbol]
+		if !ok {
+			continue
+		}
+		size, err := ts.mm.Size(asset, bars, account)
+		if err != nil {
+			log.Warn("Position sizing", zap.String("Symbol", asset.Symbol), zap.Error(err))
+			continue
+		}
+		price, err := lastClose(bars)
+		if err != nil || !isPositive(price) || !isPositive(size) {
+			log.Warn("Position sizing", zap.String("Symbol", asset.Symbol), zap.Float64("Price", price), zap.Float64("Size", size), zap.Error(err))
+			continue
+		}
+		qty := math.Floor(math.Min(size, bp/price))
+		if qty < 1 {
+			continue
+		}
+		bp -= qty * price
+		listOfOrders = append(listOfOrders, brokers.Order{
+			Symbol:      asset.Symbol,
+			Side:        brokers.Buy,
+			Type:        brokers.Market,
+			TimeInForce: brokers.Day,
+			Qty:         decimal.NewFromFloat(qty),
+		})
+	}
+
+	return listOfOrders
 }
@@ -0,0 +1,100 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/d1l1x/gofin/brokers"
+	"github.com/d1l1x/gofin/indicators"
+)
+
+// flakyBroker holds a single position and fails to report it when failing is set.
+type flakyBroker struct {
+	brokers.Broker
+	position brokers.Position
+	failing  bool
+	orders   []brokers.Order
+}
+
+func (b *flakyBroker) GetOpenPositions() ([]brokers.Position, error) {
+	if b.failing {
+		return nil, errors.New("connection reset")
+	}
+	return []brokers.Position{b.position}, nil
+}
+
+func (b *flakyBroker) PlaceOrder(order brokers.Order) (string, error) {
+	b.orders = append(b.orders, order)
+	return "1", nil
+}
+
+// barsProvider returns the bars of the current check.
+type barsProvider struct {
+	bars *indicators.BarHistory
+}
+
+func (p *barsProvider) GetHistBars(symbol string, period int) (*indicators.BarHistory, error) {
+	return p.bars, nil
+}
+
+func TestCheckOpenPositionsKeepsStateOnBrokerError(t *testing.T) {
+	broker := &flakyBroker{position: brokers.Position{Symbol: "A", Quantity: 10, AvgFillPrice: 100}}
+	provider := &barsProvider{}
+	ts := NewTradingSystem(broker, nil, nil, provider)
+	ts.AddExitRule(TrailingStop{Percent: 10})
+
+	// the highest high is 120
+	provider.bars = testHistory(119)
+	if open := ts.CheckOpenPositions(); open != 1 || len(broker.orders) != 0 {
+		t.Fatalf("Expected the position to stay open, got %d open and %d orders", open, len(broker.orders))
+	}
+
+	broker.failing = true
+	if open := ts.CheckOpenPositions(); open != 1 {
+		t.Errorf("Expected the tracked position to be reported, got %d", open)
+	}
+
+	// 10% below the earlier high
+	broker.failing = false
+	provider.bars = testHistory(108)
+	ts.CheckOpenPositions()
+	if len(broker.orders) != 1 || broker.orders[0].Side != brokers.Sell {
+		t.Errorf("Expected the trailing stop to close the position, got orders %v", broker.orders)
+	}
+}
+
+// timedBroker looks up the entry time of its position like the Alpaca broker.
+type timedBroker struct {
+	flakyBroker
+	openedAt time.Time
+	lookups  int
+}
+
+func (b *timedBroker) PositionOpenedAt(p brokers.Position) (time.Time, error) {
+	b.lookups++
+	return b.openedAt, nil
+}
+
+func TestCheckOpenPositionsResolvesEntryTimeOnce(t *testing.T) {
+	bars := datedHistory(100, 101, 102, 103)
+	broker := &timedBroker{
+		flakyBroker: flakyBroker{position: brokers.Position{Symbol: "A", Quantity: 10, AvgFillPrice: 100}},
+		openedAt:    bars.Time[1],
+	}
+	provider := &barsProvider{}
+	ts := NewTradingSystem(broker, nil, nil, provider)
+	ts.AddExitRule(MaxHoldingDays{Days: 2})
+
+	for end := 2; end <= bars.Len(); end++ {
+		sub := bars.Sub(0, end)
+		provider.bars = &sub
+		ts.CheckOpenPositions()
+	}
+	if broker.lookups != 1 {
+		t.Errorf("Expected a single lookup of the entry time, got %d", broker.lookups)
+	}
+	if len(broker.orders) != 1 {
+		t.Errorf("Expected the time exit to close the position after 2 days, got orders %v", broker.orders)
+	}
+}
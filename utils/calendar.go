@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"github.com/d1l1x/gofin/indicators"
 	"github.com/rickar/cal/v2"
 	"github.com/rickar/cal/v2/us"
 	"go.uber.org/zap"
@@ -208,3 +209,24 @@ func (c *TradingCalendar) NextBusinessDay(t time.Time) time.Time {
 		}
 	}
 }
+
+// MissingDays returns the trading days between the first and the last bar of the given history
+// for which no bar exists. The bars must carry timestamps; only the dates are compared.
+func (c *TradingCalendar) MissingDays(bars *indicators.BarHistory) []time.Time {
+	var missing []time.Time
+	if !bars.HasTime() {
+		return missing
+	}
+	have := make(map[string]bool, len(bars.Time))
+	for _, t := range bars.Time {
+		have[t.Format("2006-01-02")] = true
+	}
+	first := bars.Time[0]
+	last := bars.Time[len(bars.Time)-1]
+	for t := first; !t.After(last); t = t.AddDate(0, 0, 1) {
+		if c.IsTradingDay(t) && !have[t.Format("2006-01-02")] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"github.com/d1l1x/gofin/indicators"
 	"testing"
 	"time"
 )
@@ -174,3 +175,22 @@ func TestIsOnOpenUS(t *testing.T) {
 		t.Errorf("IsOnOpen() failed, expected false, got true")
 	}
 }
+
+func TestMissingDays(t *testing.T) {
+	calendar, _ := NewTradingCalendarUS()
+
+	// Juneteenth and the weekend are no trading days, June 21st is missing
+	bars := &indicators.BarHistory{
+		Time: []time.Time{
+			day(16), day(20), day(22),
+		},
+		Close: []float64{1, 2, 3},
+	}
+	missing := calendar.MissingDays(bars)
+	if len(missing) != 1 {
+		t.Fatalf("MissingDays() failed, expected 1 day, got %v", missing)
+	}
+	if missing[0].Day() != 21 {
+		t.Errorf("MissingDays() failed, expected June 21st, got %v", missing[0])
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"github.com/d1l1x/gofin/indicators"
 	"go.uber.org/zap"
+	"math"
 	"sort"
+	"time"
 )
 
 type Asset struct {
@@ -56,7 +58,10 @@ func (w *Watchlist) AddFilter(f Filter) {
 func (w *Watchlist) ApplyFilters(symbol string, bars *indicators.BarHistory) bool {
 	log.Debug("Apply filters", zap.String("symbol", symbol))
 	for _, filter := range w.Filters {
-		res, _ := filter.apply(bars)
+		res, err := filter.apply(bars)
+		if err != nil {
+			log.Debug("Filter failed", zap.String("symbol", symbol), zap.Error(err))
+		}
 		if !res {
 			return false
 		}
@@ -81,28 +86,108 @@ func (w *Watchlist) AddRanking(r *Ranking) {
 	w.Ranking = r
 }
 
+// ApplyRanking sets the rank of the asset to the last value of the ranking indicator. The rank
+// is NaN if the indicator fails or is still in its warm-up period.
 func (w *Watchlist) ApplyRanking(asset *Asset, bars *indicators.BarHistory) {
 	if w.Ranking != nil {
 		w.Ranking.Indicator.SetInput(bars)
-		val := w.Ranking.Indicator.Compute()
-		asset.Rank = val[len(val)-1]
+		val, err := indicators.Latest(w.Ranking.Indicator)
+		if err != nil {
+			log.Debug("Ranking failed", zap.String("symbol", asset.Symbol), zap.Error(err))
+		}
+		asset.Rank = val
 	}
 }
 
+// ApplyCrossSectionalRanking sets the rank of every asset to the percentile rank of the
+// ranking indicator across all assets at the latest timestamp all non-empty histories share,
+// see indicators.CrossSectionalRank. Unlike the raw value the percentile is comparable across
+// dates and indicators. The rank is NaN for assets without history or without a value at that
+// timestamp, and for all assets if the histories share none. Failures of the indicator are
+// logged per asset.
+func (w *Watchlist) ApplyCrossSectionalRanking(histories map[string]*indicators.BarHistory) error {
+	if w.Ranking == nil {
+		return nil
+	}
+	ranks, err := indicators.CrossSectionalRank(w.Ranking.Indicator, histories)
+	var failed indicators.RankError
+	if errors.As(err, &failed) {
+		for symbol, err := range failed {
+			log.Warn("Ranking failed", zap.String("symbol", symbol), zap.Error(err))
+		}
+	} else if err != nil {
+		return err
+	}
+
+	latest := latestCommonTime(histories)
+	for i := range w.Assets {
+		w.Assets[i].Rank = math.NaN()
+		bars, ok := histories[w.Assets[i].Symbol]
+		if !ok {
+			continue
+		}
+		for j := bars.Len() - 1; j >= 0 && !bars.Time[j].Before(latest); j-- {
+			if bars.Time[j].Equal(latest) {
+				w.Assets[i].Rank = ranks[w.Assets[i].Symbol][j]
+			}
+		}
+	}
+	return nil
+}
+
+// latestCommonTime returns the latest timestamp shared by all non-empty histories or the
+// zero time if they share none.
+func latestCommonTime(histories map[string]*indicators.BarHistory) time.Time {
+	counts := make(map[int64]int)
+	n := 0
+	for _, bars := range histories {
+		if bars.Len() == 0 {
+			continue
+		}
+		n++
+		for _, t := range bars.Time {
+			counts[t.UnixNano()]++
+		}
+	}
+	var latest time.Time
+	for _, bars := range histories {
+		for _, t := range bars.Time {
+			if counts[t.UnixNano()] == n && t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	return latest
+}
+
+// RankAssets sorts the assets by rank, assets with a NaN rank are put last.
 func (w *Watchlist) RankAssets(assets []Asset) {
 	if w.Ranking != nil {
 		switch w.Ranking.Order {
 		case Ascending:
-			sort.Slice(assets, func(i, j int) bool { return (assets)[i].Rank < (assets)[j].Rank })
+			sort.Slice(assets, func(i, j int) bool { return rankBefore(assets[i].Rank, assets[j].Rank, false) })
 		case Descending:
-			sort.Slice(assets, func(i, j int) bool { return (assets)[i].Rank > (assets)[j].Rank })
+			sort.Slice(assets, func(i, j int) bool { return rankBefore(assets[i].Rank, assets[j].Rank, true) })
 		}
 	}
 }
 
+func rankBefore(a, b float64, descending bool) bool {
+	switch {
+	case math.IsNaN(a):
+		return false
+	case math.IsNaN(b):
+		return true
+	case descending:
+		return a > b
+	default:
+		return a < b
+	}
+}
+
 // NewFilter creates a new Filter with the given indicator, comparison operator, and value.
 // The indicator should be an implementation of the Indicator interface, which has a Compute method
-// that returns a slice of float64 values.
+// that returns a slice of float64 values and an error.
 //
 // The operator should be one of the Comparison constants: LT (less than), GT (greater than),
 // LE (less than or equal to), or GE (greater than or equal to).
@@ -139,14 +224,15 @@ func NewFilter(indicator indicators.Indicator, operator Comparison, value interf
 // in the slice is used for the comparison.
 //
 // If the Filter's value is a float64, it is used directly for the comparison. If the Filter's value
-// is an Indicator, its Compute method is called to get a slice of float64 values, and the last value
-// in the slice is used for the comparison.
+// is an Indicator, it is computed on the same bars and the last value in the slice is used for the
+// comparison.
 //
 // The comparison operator should be one of the Comparison constants: LT (less than), GT (greater than),
 // LE (less than or equal to), or GE (greater than or equal to).
 //
-// If the Filter's value is neither a float64 nor an Indicator, or if the comparison operator is not
-// a known Comparison, the method returns false.
+// If the Filter's value is neither a float64 nor an Indicator, if the comparison operator is not
+// a known Comparison or if an indicator fails, the method returns false and an error. A NaN value,
+// e.g. of an indicator in its warm-up period, fails every comparison.
 //
 // Usage:
 //
@@ -159,8 +245,10 @@ func (f Filter) apply(bars *indicators.BarHistory) (bool, error) {
 
 	f.Indicator.SetInput(bars)
 
-	val := f.Indicator.Compute()
-	indicatorValue := val[len(val)-1]
+	indicatorValue, err := indicators.Latest(f.Indicator)
+	if err != nil {
+		return false, err
+	}
 
 	var compareValue float64
 
@@ -169,8 +257,11 @@ func (f Filter) apply(bars *indicators.BarHistory) (bool, error) {
 	case float64:
 		compareValue = v
 	case indicators.Indicator:
-		res := v.Compute()
-		compareValue = res[len(res)-1]
+		v.SetInput(bars)
+		compareValue, err = indicators.Latest(v)
+		if err != nil {
+			return false, err
+		}
 	default:
 		return false, errors.New("the provided comparison value is neither a float64 nor an Indicator")
 	}
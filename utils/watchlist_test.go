@@ -1,14 +1,33 @@
 package utils
 
 import (
+	"errors"
 	"github.com/d1l1x/gofin/indicators"
+	"github.com/d1l1x/gofin/patterns"
+	"math"
 	"testing"
+	"time"
 )
 
+// day returns the given day of June 2023.
+func day(d int) time.Time {
+	return time.Date(2023, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dated returns bars with the closes on consecutive days starting at the given day.
+func dated(first int, closes ...float64) *indicators.BarHistory {
+	bars := &indicators.BarHistory{Close: closes}
+	for i := range closes {
+		bars.Time = append(bars.Time, day(first+i))
+	}
+	return bars
+}
+
 type MockIndicator struct {
 	Input  []float64
 	Values []float64
 	Bars   *indicators.BarHistory
+	Err    error
 }
 
 func NewMockIndicator(values []float64) *MockIndicator {
@@ -22,8 +41,8 @@ func NewMockIndicator(values []float64) *MockIndicator {
 	return &MockIndicator{Values: values, Bars: bars}
 }
 
-func (m *MockIndicator) Compute() []float64 {
-	return m.Values
+func (m *MockIndicator) Compute() ([]float64, error) {
+	return m.Values, m.Err
 }
 
 func (m *MockIndicator) SetInput(bars *indicators.BarHistory) {
@@ -257,3 +276,276 @@ func TestFilterApplyUnknownComp(t *testing.T) {
 		t.Errorf("Expected filter to return error, got nil")
 	}
 }
+
+func TestFilterApplyNaN(t *testing.T) {
+	mock := NewMockIndicator([]float64{1, 2, 3, 4, math.NaN()})
+	for _, op := range []Comparison{LT, GT, LE, GE} {
+		res, err := NewFilter(mock, op, 3.0).apply(mock.Bars)
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if res {
+			t.Errorf("Expected NaN to fail comparison %d", op)
+		}
+	}
+}
+
+func TestFilterApplyIndicatorError(t *testing.T) {
+	mock := NewMockIndicator([]float64{1, 2, 3})
+	mock.Err = errors.New("not enough bars")
+
+	res, err := NewFilter(mock, GT, 0.0).apply(mock.Bars)
+	if err == nil || res {
+		t.Errorf("Expected filter to fail with error, got %v, %v", res, err)
+	}
+}
+
+func TestFilterApplyIndicators(t *testing.T) {
+	bars := &indicators.BarHistory{Close: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+
+	// close above its 5 day average
+	filter := NewFilter(indicators.Last(indicators.BarHistory{}, indicators.Close), GT, indicators.MA(nil, 5))
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+}
+
+func TestRankAssetsNaNLast(t *testing.T) {
+	w := &Watchlist{Ranking: &Ranking{Order: Descending}}
+	assets := []Asset{{Symbol: "A", Rank: 1}, {Symbol: "B", Rank: math.NaN()}, {Symbol: "C", Rank: 3}}
+	w.RankAssets(assets)
+	if assets[0].Symbol != "C" || assets[1].Symbol != "A" || assets[2].Symbol != "B" {
+		t.Errorf("Unexpected order: %v", assets)
+	}
+
+	w.Ranking.Order = Ascending
+	w.RankAssets(assets)
+	if assets[0].Symbol != "A" || assets[1].Symbol != "C" || assets[2].Symbol != "B" {
+		t.Errorf("Unexpected order: %v", assets)
+	}
+}
+
+func TestApplyRankingWarmUp(t *testing.T) {
+	w := &Watchlist{Ranking: &Ranking{Indicator: indicators.ROC(nil, 5), Order: Descending}}
+	asset := Asset{Symbol: "A"}
+	w.ApplyRanking(&asset, &indicators.BarHistory{Close: []float64{1, 2, 3}})
+	if !math.IsNaN(asset.Rank) {
+		t.Errorf("Expected NaN rank, got %v", asset.Rank)
+	}
+}
+
+func TestFilterAndRankByOutput(t *testing.T) {
+	bars := &indicators.BarHistory{Close: []float64{10, 11, 10, 11, 10, 11, 10, 12}}
+
+	// close above the upper band
+	filter := NewFilter(indicators.Output(indicators.BB(nil, 5).WithFactor(1), indicators.BBPercentB), GT, 1.0)
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+
+	w := &Watchlist{Ranking: &Ranking{Indicator: indicators.Output(indicators.BB(nil, 5), indicators.BBBandwidth), Order: Descending}}
+	asset := Asset{Symbol: "A"}
+	w.ApplyRanking(&asset, bars)
+	if math.IsNaN(asset.Rank) || asset.Rank <= 0 {
+		t.Errorf("Expected positive band width, got %v", asset.Rank)
+	}
+}
+
+func TestFilterBreakout(t *testing.T) {
+	bars := &indicators.BarHistory{
+		High:  []float64{10, 11, 12, 11, 10, 14},
+		Low:   []float64{9, 10, 11, 10, 9, 12},
+		Close: []float64{10, 11, 11, 10, 10, 13.5},
+	}
+	// close above the 3 day high of the previous bars
+	high := indicators.Shift(indicators.Output(indicators.Donchian(indicators.BarHistory{}, 3), indicators.ChannelUpper), 1)
+	filter := NewFilter(indicators.Last(indicators.BarHistory{}, indicators.Close), GT, high)
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+
+	prev := bars.Sub(0, 5)
+	res, _ = filter.apply(&prev)
+	if res {
+		t.Errorf("Expected filter to return false, got true")
+	}
+}
+
+func TestFilterOscillator(t *testing.T) {
+	bars := &indicators.BarHistory{
+		High:  []float64{10, 12, 11, 13, 14},
+		Low:   []float64{8, 9, 9, 10, 12},
+		Close: []float64{9, 11, 10, 12, 13},
+	}
+	// overbought by CCI, rising by the Chande momentum
+	for _, filter := range []*Filter{
+		NewFilter(indicators.CCI(indicators.BarHistory{}, 3), GT, 90.0),
+		NewFilter(indicators.CMO(nil, 2), GE, 100.0),
+	} {
+		res, err := filter.apply(bars)
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %v ", err)
+		}
+		if !res {
+			t.Errorf("Expected filter to return true, got false")
+		}
+	}
+}
+
+func TestRankByAdjustedSlope(t *testing.T) {
+	w := &Watchlist{Ranking: &Ranking{
+		Indicator: indicators.Output(indicators.LinReg(nil, 5).WithLog(), indicators.LinRegAdjustedSlope),
+		Order:     Descending,
+	}}
+	// both rise by 20%, but only the first one steadily
+	steady := Asset{Symbol: "A"}
+	w.ApplyRanking(&steady, &indicators.BarHistory{Close: []float64{100, 104, 109, 113, 118, 120}})
+	erratic := Asset{Symbol: "B"}
+	w.ApplyRanking(&erratic, &indicators.BarHistory{Close: []float64{100, 112, 101, 115, 104, 120}})
+
+	assets := []Asset{erratic, steady}
+	w.RankAssets(assets)
+	if assets[0].Symbol != "A" {
+		t.Errorf("Expected steady trend first, got %v", assets)
+	}
+}
+
+func TestFilterReversalCandle(t *testing.T) {
+	bars := &indicators.BarHistory{
+		Open:  []float64{10, 8.8},
+		High:  []float64{10.5, 10.6},
+		Low:   []float64{8.5, 8.7},
+		Close: []float64{9, 10.2},
+	}
+	// bullish engulfing or harami as trigger
+	filter := NewFilter(patterns.Detect(indicators.BarHistory{}, patterns.Engulfing, patterns.Harami), GT, 0.0)
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+}
+
+func TestFilterWeeklyTrend(t *testing.T) {
+	bars := &indicators.BarHistory{}
+	// starts on a Monday
+	for i := 0; i < 30; i++ {
+		p := float64(100 + i)
+		bars.Time = append(bars.Time, day(5+i))
+		bars.Open = append(bars.Open, p)
+		bars.High = append(bars.High, p+1)
+		bars.Low = append(bars.Low, p-1)
+		bars.Close = append(bars.Close, p)
+	}
+	// weekly close above its 2 week average
+	filter := NewFilter(
+		indicators.OnTimeframe(indicators.Last(indicators.BarHistory{}, indicators.Close), indicators.Weekly),
+		GT,
+		indicators.OnTimeframe(indicators.MA(nil, 2), indicators.Weekly),
+	)
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+}
+
+func TestRankAgainstBenchmark(t *testing.T) {
+	index := dated(1, 100, 101, 102, 103)
+	histories := map[string]*indicators.BarHistory{
+		"A": dated(1, 10, 10, 10, 10),
+		"B": dated(1, 10, 11, 12, 13),
+		"C": dated(1, 10, 10.1, 10.2, 10.3),
+	}
+	w := &Watchlist{
+		Assets:  []Asset{{Symbol: "A"}, {Symbol: "B"}, {Symbol: "C"}, {Symbol: "D"}},
+		Ranking: &Ranking{Indicator: indicators.MansfieldRS(indicators.BarHistory{}, *index, 3), Order: Descending},
+	}
+	if err := w.ApplyCrossSectionalRanking(histories); err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{0, 100, 50, math.NaN()}
+	for i, asset := range w.Assets {
+		if asset.Rank != want[i] && !(math.IsNaN(asset.Rank) && math.IsNaN(want[i])) {
+			t.Errorf("Expected rank %v of %s, got %v", want[i], asset.Symbol, asset.Rank)
+		}
+	}
+
+	w.RankAssets(w.Assets)
+	if w.Assets[0].Symbol != "B" || w.Assets[3].Symbol != "D" {
+		t.Errorf("Unexpected order: %v", w.Assets)
+	}
+}
+
+func TestCrossSectionalRankingAtLatestCommonDate(t *testing.T) {
+	histories := map[string]*indicators.BarHistory{
+		"A": dated(1, 10, 11, 9),
+		"B": dated(1, 10, 12, 9),
+		// lacks the last day, hence all assets are ranked on the second day
+		"C": dated(1, 10, 13),
+		// a single bar fails the rate of change
+		"D": dated(2, 10),
+	}
+	w := &Watchlist{
+		Assets:  []Asset{{Symbol: "A"}, {Symbol: "B"}, {Symbol: "C"}, {Symbol: "D"}, {Symbol: "E"}},
+		Ranking: &Ranking{Indicator: indicators.ROC(nil, 1), Order: Descending},
+	}
+	if err := w.ApplyCrossSectionalRanking(histories); err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	want := []float64{0, 50, 100, math.NaN(), math.NaN()}
+	for i, asset := range w.Assets {
+		if asset.Rank != want[i] && !(math.IsNaN(asset.Rank) && math.IsNaN(want[i])) {
+			t.Errorf("Expected rank %v of %s, got %v", want[i], asset.Symbol, asset.Rank)
+		}
+	}
+
+	// without a common date no asset is ranked
+	histories["D"] = dated(3, 10)
+	if err := w.ApplyCrossSectionalRanking(histories); err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	for _, asset := range w.Assets {
+		if !math.IsNaN(asset.Rank) {
+			t.Errorf("Expected NaN rank of %s, got %v", asset.Symbol, asset.Rank)
+		}
+	}
+}
+
+func TestFilterDrawdown(t *testing.T) {
+	bars := &indicators.BarHistory{Close: []float64{100, 110, 99, 104.5, 88, 121}}
+	// at most 12% drawdown within the last 3 bars
+	filter := NewFilter(indicators.MaxDrawdown(nil, 3), LE, 12.0)
+	res, err := filter.apply(bars)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if res {
+		t.Errorf("Expected filter to return false, got true")
+	}
+
+	prev := bars.Sub(0, 4)
+	res, err = filter.apply(&prev)
+	if err != nil {
+		t.Fatalf("Unexpected error occurred: %v ", err)
+	}
+	if !res {
+		t.Errorf("Expected filter to return true, got false")
+	}
+}